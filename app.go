@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"rest-api/internal/config"
+	"rest-api/internal/migrations"
+	"rest-api/internal/repositories"
+	"rest-api/internal/services"
+	"rest-api/internal/storage"
+	"rest-api/internal/utils"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// app holds the database connection and the layers built on it, shared by
+// the server and the admin commands
+type app struct {
+	db              *mongo.Database
+	storage         storage.Storage
+	migrator        *migrations.Migrator
+	userRepo        *repositories.UserRepository
+	userService     *services.UserService
+	productService  *services.ProductService
+	categoryService *services.CategoryService
+	orderService    *services.OrderService
+	rateService     *services.ExchangeRateService
+}
+
+func newApp(ctx context.Context, cfg *config.Config) (*app, error) {
+	db, err := config.ConnectDatabase(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := storage.New(ctx, cfg.Storage)
+	if err != nil {
+		db.Client().Disconnect(ctx)
+		return nil, fmt.Errorf("set up storage: %w", err)
+	}
+
+	// Initialize validator
+	validate := utils.NewValidator()
+
+	// Initialize repositories
+	userRepo := repositories.NewUserRepository(db)
+	productRepo := repositories.NewProductRepository(db, userRepo)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+	rateRepo := repositories.NewExchangeRateRepository(db)
+
+	return &app{
+		db:              db,
+		storage:         store,
+		migrator:        migrations.NewMigrator(db),
+		userRepo:        userRepo,
+		userService:     services.NewUserService(userRepo, validate),
+		productService:  services.NewProductService(productRepo, categoryRepo, rateRepo, store, cfg.Images, validate),
+		categoryService: services.NewCategoryService(categoryRepo, productRepo, validate),
+		orderService:    services.NewOrderService(orderRepo, productRepo, rateRepo, validate),
+		rateService:     services.NewExchangeRateService(rateRepo, validate),
+	}, nil
+}
+
+// close disconnects from MongoDB
+func (a *app) close(ctx context.Context) error {
+	return a.db.Client().Disconnect(ctx)
+}
@@ -2,7 +2,12 @@
 
 # Build the application
 echo "Building the application..."
-go build -o bin/rest-api main.go
+go build -ldflags "-X rest-api/internal/buildinfo.Commit=$(git rev-parse --short HEAD 2>/dev/null)" -o bin/rest-api .
+go build -o bin/restctl ./cmd/restctl
+
+# Fail if routes and the OpenAPI operations table have diverged
+echo "Checking the OpenAPI document..."
+./bin/rest-api openapi > /dev/null || exit 1
 
 # Run the application
 echo "Starting the REST API server..."
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"rest-api/pkg/client"
+)
+
+const testPassword = "secret-password"
+
+// registerUser creates a user through the API and returns its email
+func registerUser(t *testing.T, c *client.Client, name string) string {
+	t.Helper()
+
+	email := name + "@example.com"
+	if _, err := c.Register(context.Background(), &client.CreateUserRequest{Name: name, Email: email, Password: testPassword}); err != nil {
+		t.Fatalf("register %s: %v", name, err)
+	}
+	return email
+}
+
+func newTestClient(t *testing.T, url string, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	c, err := client.New(url, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// expiredToken returns an unsigned JWT whose exp claim has passed
+func expiredToken() string {
+	claims := fmt.Sprintf(`{"exp":%d}`, time.Now().Add(-time.Hour).Unix())
+	return "e30." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".c2ln"
+}
+
+func TestClientLogin(t *testing.T) {
+	a, cfg := newTestApp(t)
+	server := newTestServer(t, a, cfg)
+	ctx := context.Background()
+
+	c := newTestClient(t, server.URL)
+	email := registerUser(t, c, "alice")
+
+	if _, err := c.Profile(ctx); !errors.Is(err, client.ErrNotLoggedIn) {
+		t.Fatalf("Profile before login: err = %v, want ErrNotLoggedIn", err)
+	}
+
+	login, err := c.Login(ctx, email, testPassword)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if login.Token == "" || c.Token() != login.Token {
+		t.Fatalf("client token = %q, want the login token %q", c.Token(), login.Token)
+	}
+
+	user, err := c.Profile(ctx)
+	if err != nil {
+		t.Fatalf("Profile: %v", err)
+	}
+	if user.Email != email {
+		t.Errorf("profile email = %q, want %q", user.Email, email)
+	}
+}
+
+func TestClientRenewsExpiredToken(t *testing.T) {
+	a, cfg := newTestApp(t)
+	server := newTestServer(t, a, cfg)
+	ctx := context.Background()
+
+	email := registerUser(t, newTestClient(t, server.URL), "bob")
+
+	var renewed []string
+	c := newTestClient(t, server.URL,
+		client.WithToken(expiredToken()),
+		client.WithCredentials(email, testPassword),
+		client.WithTokenHook(func(token string) { renewed = append(renewed, token) }),
+	)
+
+	if _, err := c.Profile(ctx); err != nil {
+		t.Fatalf("Profile with an expired token: %v", err)
+	}
+	if len(renewed) != 1 || renewed[0] != c.Token() {
+		t.Errorf("token hook called with %q, want the one new token %q", renewed, c.Token())
+	}
+}
+
+func TestClientRetriesRejectedToken(t *testing.T) {
+	a, cfg := newTestApp(t)
+	server := newTestServer(t, a, cfg)
+	ctx := context.Background()
+
+	email := registerUser(t, newTestClient(t, server.URL), "carol")
+
+	// A token without an exp claim is sent as is and rejected by the server
+	rejected := "not-a-jwt"
+	c := newTestClient(t, server.URL, client.WithToken(rejected), client.WithCredentials(email, testPassword))
+
+	user, err := c.Profile(ctx)
+	if err != nil {
+		t.Fatalf("Profile after a rejected token: %v", err)
+	}
+	if user.Email != email {
+		t.Errorf("profile email = %q, want %q", user.Email, email)
+	}
+	if c.Token() == rejected {
+		t.Error("client kept the rejected token")
+	}
+
+	// Without credentials the 401 is returned
+	c = newTestClient(t, server.URL, client.WithToken(rejected))
+	if _, err := c.Profile(ctx); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("Profile without credentials: err = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestClientPagination(t *testing.T) {
+	a, cfg := newTestApp(t)
+	server := newTestServer(t, a, cfg)
+	ctx := context.Background()
+
+	c := newTestClient(t, server.URL)
+	const users = 5
+	var email string
+	for i := range users {
+		email = registerUser(t, c, fmt.Sprintf("user%d", i))
+	}
+	if _, err := c.Login(ctx, email, testPassword); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	page, err := c.ListUsers(ctx, &client.ListOptions{Page: 3, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if len(page.Items) != 1 || page.Total != users || page.HasNext() {
+		t.Errorf("page 3 has %d of %d users, HasNext %t; want 1 of %d and no next page", len(page.Items), page.Total, page.HasNext(), users)
+	}
+
+	seen := make(map[string]bool)
+	for user, err := range c.Users(ctx, 2) {
+		if err != nil {
+			t.Fatalf("Users: %v", err)
+		}
+		if seen[user.ID] {
+			t.Errorf("user %s listed twice", user.ID)
+		}
+		seen[user.ID] = true
+	}
+	if len(seen) != users {
+		t.Errorf("Users yielded %d users, want %d", len(seen), users)
+	}
+}
+
+func TestClientProblemErrors(t *testing.T) {
+	a, cfg := newTestApp(t)
+	server := newTestServer(t, a, cfg)
+	ctx := context.Background()
+
+	c := newTestClient(t, server.URL)
+
+	_, err := c.GetProduct(ctx, "65f000000000000000000000")
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetProduct of a missing product: err = %v, want *client.Error", err)
+	}
+	if !errors.Is(err, client.ErrNotFound) || apiErr.Code != "NOT_FOUND" || apiErr.RequestID == "" {
+		t.Errorf("missing product error = %+v, want a 404 NOT_FOUND with a request ID", apiErr)
+	}
+
+	_, err = c.Register(ctx, &client.CreateUserRequest{Name: "dave", Email: "not-an-email", Password: testPassword})
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Register with an invalid email: err = %v, want *client.Error", err)
+	}
+	if !errors.Is(err, client.ErrValidation) || apiErr.Code != "VALIDATION_FAILED" {
+		t.Errorf("validation error = %+v, want a 422 VALIDATION_FAILED", apiErr)
+	}
+	if len(apiErr.FieldErrors) != 1 || apiErr.FieldErrors[0].Field != "email" {
+		t.Errorf("field errors = %+v, want one for email", apiErr.FieldErrors)
+	}
+}
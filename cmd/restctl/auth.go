@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"rest-api/pkg/client"
+
+	"golang.org/x/term"
+)
+
+func runLogin(ctx context.Context, args []string) error {
+	flags, opts := newFlags("login", "--email EMAIL [--password-stdin]")
+	email := flags.String("email", "", "account email (required)")
+	passwordStdin := flags.Bool("password-stdin", false, "read the password from stdin instead of prompting")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	if *email == "" {
+		fmt.Fprintln(os.Stderr, "--email is required")
+		flags.Usage()
+		return errUsage
+	}
+
+	creds, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+	server := serverURL(opts, creds)
+
+	password, err := readPassword(*passwordStdin)
+	if err != nil {
+		return err
+	}
+
+	api, err := client.New(server, client.WithUserAgent("restctl"))
+	if err != nil {
+		return err
+	}
+	resp, err := api.Login(ctx, *email, password)
+	if err != nil {
+		return err
+	}
+
+	creds = &credentials{Server: server, Email: resp.User.Email, Token: resp.Token}
+	if err := creds.save(); err != nil {
+		return fmt.Errorf("store credentials: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Logged in to %s as %s\n", server, resp.User.Email)
+	return nil
+}
+
+func runLogout(_ context.Context, args []string) error {
+	flags, _ := newFlags("logout", "")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	creds, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+	creds.Token = ""
+	return creds.save()
+}
+
+// readPassword prompts for the password without echo, or reads a line
+// from stdin when it is not a terminal or fromStdin is set
+func readPassword(fromStdin bool) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !fromStdin && term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, "Password: ")
+		password, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(password), err
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		if err == nil {
+			err = errors.New("empty password")
+		}
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	return password, nil
+}
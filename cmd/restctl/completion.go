@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// completion returns a command that prints a completion script
+func completion(script string) command {
+	return func(_ context.Context, args []string) error {
+		if len(args) > 0 {
+			return errUsage
+		}
+		fmt.Print(script)
+		return nil
+	}
+}
+
+const bashCompletion = `# bash completion for restctl; load with: source <(restctl completion bash)
+_restctl() {
+    local cur=${COMP_WORDS[COMP_CWORD]}
+    local words
+    case $COMP_CWORD in
+    1) words="login logout products categories users orders rates completion help" ;;
+    2)
+        case ${COMP_WORDS[1]} in
+        products) words="list get create update delete upload-image" ;;
+        categories) words="list" ;;
+        users) words="me" ;;
+        orders) words="list get place" ;;
+        rates) words="list set delete" ;;
+        completion) words="bash zsh fish" ;;
+        esac
+        ;;
+    esac
+    if [[ -z $words ]]; then
+        case ${COMP_WORDS[COMP_CWORD-1]} in
+        -o) words="table json yaml" ;;
+        *) words="--server -o --email --password-stdin --mine --category --descendants --all --page --limit --name --description --price --currency --stock --tags --item" ;;
+        esac
+    fi
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+complete -F _restctl restctl
+`
+
+const zshCompletion = `#compdef restctl
+# zsh completion for restctl; load with: source <(restctl completion zsh)
+_restctl() {
+    local -a flags=(--server -o --email --password-stdin --mine --category --descendants --all --page --limit --name --description --price --currency --stock --tags --item)
+    case $CURRENT in
+    2) compadd login logout products categories users orders rates completion help ;;
+    3)
+        case $words[2] in
+        products) compadd list get create update delete upload-image ;;
+        categories) compadd list ;;
+        users) compadd me ;;
+        orders) compadd list get place ;;
+        rates) compadd list set delete ;;
+        completion) compadd bash zsh fish ;;
+        *) compadd -- $flags ;;
+        esac
+        ;;
+    *)
+        if [[ $words[CURRENT-1] == -o ]]; then
+            compadd table json yaml
+        else
+            compadd -- $flags
+        fi
+        ;;
+    esac
+}
+compdef _restctl restctl
+`
+
+const fishCompletion = `# fish completion for restctl; load with: restctl completion fish | source
+complete -c restctl -f
+complete -c restctl -n __fish_use_subcommand -a "login logout products categories users orders rates completion help"
+complete -c restctl -n "__fish_seen_subcommand_from products" -a "list get create update delete upload-image"
+complete -c restctl -n "__fish_seen_subcommand_from categories" -a list
+complete -c restctl -n "__fish_seen_subcommand_from users" -a me
+complete -c restctl -n "__fish_seen_subcommand_from orders" -a "list get place"
+complete -c restctl -n "__fish_seen_subcommand_from rates" -a "list set delete"
+complete -c restctl -n "__fish_seen_subcommand_from completion" -a "bash zsh fish"
+complete -c restctl -o o -x -a "table json yaml" -d "output format"
+complete -c restctl -l server -x -d "API base URL"
+complete -c restctl -n "__fish_seen_subcommand_from login" -l email -x
+complete -c restctl -n "__fish_seen_subcommand_from login" -l password-stdin
+complete -c restctl -n "__fish_seen_subcommand_from list" -l mine
+complete -c restctl -n "__fish_seen_subcommand_from list" -l category -x
+complete -c restctl -n "__fish_seen_subcommand_from list" -l descendants
+complete -c restctl -n "__fish_seen_subcommand_from list" -l all
+complete -c restctl -n "__fish_seen_subcommand_from list" -l page -x
+complete -c restctl -n "__fish_seen_subcommand_from list" -l limit -x
+complete -c restctl -n "__fish_seen_subcommand_from create update" -l name -x
+complete -c restctl -n "__fish_seen_subcommand_from create update" -l description -x
+complete -c restctl -n "__fish_seen_subcommand_from create update" -l price -x
+complete -c restctl -n "__fish_seen_subcommand_from create update" -l stock -x
+complete -c restctl -n "__fish_seen_subcommand_from create update" -l tags -x
+complete -c restctl -n "__fish_seen_subcommand_from place" -l item -x
+complete -c restctl -n "__fish_seen_subcommand_from create update get place" -l currency -x
+`
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"rest-api/pkg/client"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// credentials is what login stores between runs. Only the token is kept,
+// never the password.
+type credentials struct {
+	Server string `json:"server"`
+	Email  string `json:"email"`
+	Token  string `json:"token"`
+}
+
+// credentialsPath returns $RESTCTL_CREDENTIALS, or credentials.json in the
+// user's configuration directory
+func credentialsPath() (string, error) {
+	if path := os.Getenv("RESTCTL_CREDENTIALS"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "restctl", "credentials.json"), nil
+}
+
+// loadCredentials returns the stored credentials, which are empty before
+// the first login
+func loadCredentials() (*credentials, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &credentials{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var creds credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return &creds, nil
+}
+
+// save writes the credentials readable by the current user only
+func (c *credentials) save() error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// options are the flags every command accepts
+type options struct {
+	server string
+	output string
+}
+
+// newFlags creates the flag set of a command with the common flags
+func newFlags(name, args string) (*flag.FlagSet, *options) {
+	opts := &options{}
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(&opts.server, "server", "", "API base URL")
+	flags.StringVar(&opts.output, "o", formatTable, "output format: table, json or yaml")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: restctl %s %s\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags, opts
+}
+
+// parse parses args, which must hold exactly nargs positional arguments
+// after the flags; flags may also follow them
+func parse(flags *flag.FlagSet, args []string, nargs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != nargs {
+		fmt.Fprintf(flags.Output(), "expected %d argument(s), got %d\n", nargs, len(positional))
+		flags.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// newClient creates an API client for the chosen server, using the stored
+// token when it was issued by that server
+func newClient(opts *options) (*client.Client, error) {
+	if err := checkFormat(opts.output); err != nil {
+		return nil, err
+	}
+
+	creds, err := loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	server := serverURL(opts, creds)
+	clientOpts := []client.Option{client.WithUserAgent("restctl")}
+	if creds.Token != "" && strings.TrimSuffix(creds.Server, "/") == server {
+		clientOpts = append(clientOpts, client.WithToken(creds.Token))
+	}
+	return client.New(server, clientOpts...)
+}
+
+// serverURL picks the server from --server, $RESTCTL_SERVER, the stored
+// credentials or the default, in that order
+func serverURL(opts *options, creds *credentials) string {
+	for _, server := range []string{opts.server, os.Getenv("RESTCTL_SERVER"), creds.Server} {
+		if server != "" {
+			return strings.TrimSuffix(server, "/")
+		}
+	}
+	return defaultServer
+}
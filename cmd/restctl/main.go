@@ -0,0 +1,125 @@
+// Command restctl is a command-line client for the REST API, built on
+// pkg/client.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"rest-api/pkg/client"
+)
+
+const usage = `usage: restctl <command> [flags]
+
+Commands:
+  login --email EMAIL              log in and store the token
+  logout                           forget the stored token
+  products list [--mine] [--all]   list products, or --category SLUG [--descendants]
+  products get ID [--currency C]   show a product, with prices converted into currency C
+  products create --name ...       create a product
+  products update ID --price ...   change the given fields of a product
+  products delete ID               delete a product
+  products upload-image ID FILE    add an image to a product
+  categories list                  list the category tree
+  users me                         show the logged-in user
+  orders list [--all]              list your orders
+  orders get ID                    show an order
+  orders place --item ID[:VARIANT][=N] ... [--currency C]
+                                   order products, by variant for products with variants,
+                                   converting prices into currency C
+  rates list                       list exchange rates
+  rates set BASE QUOTE RATE        set the price of one BASE in QUOTE (admin)
+  rates delete BASE QUOTE          delete an exchange rate (admin)
+  completion bash|zsh|fish         print a shell completion script
+
+Every command accepts --server URL (default $RESTCTL_SERVER, the server
+logged in to, or http://localhost:8080) and -o table|json|yaml.
+`
+
+// errUsage reports invalid arguments, which exit with status 2
+var errUsage = errors.New("invalid usage")
+
+// command runs a leaf command with its remaining arguments
+type command func(ctx context.Context, args []string) error
+
+var commands = map[string]map[string]command{
+	"login":      {"": runLogin},
+	"logout":     {"": runLogout},
+	"products":   {"list": runProductsList, "get": runProductsGet, "create": runProductsCreate, "update": runProductsUpdate, "delete": runProductsDelete, "upload-image": runProductsUploadImage},
+	"categories": {"list": runCategoriesList},
+	"users":      {"me": runUsersMe},
+	"orders":     {"list": runOrdersList, "get": runOrdersGet, "place": runOrdersPlace},
+	"rates":      {"list": runRatesList, "set": runRatesSet, "delete": runRatesDelete},
+	"completion": {"bash": completion(bashCompletion), "zsh": completion(zshCompletion), "fish": completion(fishCompletion)},
+	"help":       {"": func(context.Context, []string) error { fmt.Print(usage); return nil }},
+	"--help":     {"": func(context.Context, []string) error { fmt.Print(usage); return nil }},
+	"-h":         {"": func(context.Context, []string) error { fmt.Print(usage); return nil }},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	subcommands, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	name, rest := "", args[1:]
+	if _, leaf := subcommands[""]; !leaf {
+		if len(rest) == 0 {
+			fmt.Fprintf(os.Stderr, "%s requires a subcommand\n\n%s", args[0], usage)
+			return 2
+		}
+		name, rest = rest[0], rest[1:]
+	}
+
+	cmd, ok := subcommands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0]+" "+name, usage)
+		return 2
+	}
+
+	if err := cmd(ctx, rest); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		printError(err)
+		return 1
+	}
+	return 0
+}
+
+// printError writes err to stderr, listing invalid fields on their own lines
+func printError(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	if errors.Is(err, client.ErrNotLoggedIn) {
+		fmt.Fprintln(os.Stderr, "run `restctl login --email EMAIL` first")
+		return
+	}
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		return
+	}
+	for _, fieldErr := range apiErr.FieldErrors {
+		fmt.Fprintf(os.Stderr, "  %s: %s\n", fieldErr.Field, fieldErr.Message)
+	}
+	if errors.Is(err, client.ErrUnauthorized) {
+		fmt.Fprintln(os.Stderr, "run `restctl login` to log in again")
+	}
+}
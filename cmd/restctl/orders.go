@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"rest-api/pkg/client"
+)
+
+func runOrdersList(ctx context.Context, args []string) error {
+	flags, opts := newFlags("orders list", "[--all] [--page N] [--limit N]")
+	all := flags.Bool("all", false, "list every page")
+	page := flags.Int("page", 1, "page number")
+	limit := flags.Int("limit", 10, "orders per page, at most 100")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+
+	var orders []client.Order
+	if *all {
+		for order, err := range api.MyOrders(ctx, *limit) {
+			if err != nil {
+				return err
+			}
+			orders = append(orders, order)
+		}
+	} else {
+		result, err := api.ListMyOrders(ctx, &client.ListOptions{Page: *page, Limit: *limit})
+		if err != nil {
+			return err
+		}
+		orders = result.Items
+		if opts.output == formatTable {
+			defer fmt.Fprintf(os.Stderr, "page %d, %d of %d orders\n", result.Page, len(result.Items), result.Total)
+		}
+	}
+
+	if orders == nil {
+		orders = []client.Order{}
+	}
+	return render(opts.output, orders, orderTable(orders))
+}
+
+func runOrdersGet(ctx context.Context, args []string) error {
+	flags, opts := newFlags("orders get", "ID")
+	positional, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	order, err := api.GetOrder(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return renderOrder(opts.output, order)
+}
+
+func runOrdersPlace(ctx context.Context, args []string) error {
+	flags, opts := newFlags("orders place", "--item PRODUCT_ID[:VARIANT_ID][=QUANTITY] ... [--currency CODE]")
+	currency := flags.String("currency", "", "ISO 4217 code to place the order in, converting the prices")
+	var req client.CreateOrderRequest
+	flags.Func("item", "an item to order, repeatable; the quantity defaults to 1", func(value string) error {
+		item, err := parseOrderItem(value)
+		if err != nil {
+			return err
+		}
+		req.Items = append(req.Items, item)
+		return nil
+	})
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	if len(req.Items) == 0 {
+		fmt.Fprintln(os.Stderr, "at least one --item is required")
+		return errUsage
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	order, err := api.PlaceOrderIn(ctx, *currency, &req)
+	if err != nil {
+		return err
+	}
+	if opts.output == formatTable {
+		defer fmt.Fprintf(os.Stderr, "Placed order %s, total %s\n", order.ID, order.TotalAmount)
+	}
+	return renderOrder(opts.output, order)
+}
+
+// renderOrder prints an order, followed in tables by the exchange rates its
+// prices were converted at
+func renderOrder(output string, order *client.Order) error {
+	if err := render(output, order, orderItemTable(order.OrderItems)); err != nil {
+		return err
+	}
+	if output == formatTable && len(order.ExchangeRates) > 0 {
+		fmt.Println()
+		return render(output, nil, rateTable(order.ExchangeRates))
+	}
+	return nil
+}
+
+// parseOrderItem parses PRODUCT_ID[:VARIANT_ID][=QUANTITY]
+func parseOrderItem(value string) (client.OrderItemRequest, error) {
+	item := client.OrderItemRequest{Quantity: 1}
+	ids, quantity, hasQuantity := strings.Cut(value, "=")
+	if hasQuantity {
+		n, err := strconv.Atoi(quantity)
+		if err != nil || n < 1 {
+			return item, fmt.Errorf("invalid quantity %q", quantity)
+		}
+		item.Quantity = n
+	}
+	item.ProductID, item.VariantID, _ = strings.Cut(ids, ":")
+	if item.ProductID == "" {
+		return item, fmt.Errorf("missing product ID in %q", value)
+	}
+	return item, nil
+}
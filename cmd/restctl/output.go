@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"rest-api/pkg/client"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatYAML:
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "unknown output format %q: use table, json or yaml\n", format)
+	return errUsage
+}
+
+// table is the tabular form of a value
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// render writes value to stdout in format, using t for the table format
+func render(format string, value interface{}, t table) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case formatYAML:
+		// Round-trip through JSON so the keys match the API's field names
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(generic); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printRow(w, t.header)
+	for _, row := range t.rows {
+		printRow(w, row)
+	}
+	return w.Flush()
+}
+
+func printRow(w *tabwriter.Writer, cells []string) {
+	for i, cell := range cells {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, cell)
+	}
+	fmt.Fprintln(w)
+}
+
+func productTable(products []client.Product) table {
+	t := table{header: []string{"ID", "NAME", "PRICE", "STOCK", "CATEGORIES", "OWNER", "UPDATED"}}
+	for _, p := range products {
+		categories := make([]string, len(p.Categories))
+		for i, category := range p.Categories {
+			categories[i] = category.Slug
+		}
+		t.rows = append(t.rows, []string{
+			p.ID, p.Name, p.Price.String(), strconv.Itoa(p.Stock), strings.Join(categories, ","), p.User.Email, p.UpdatedAt,
+		})
+	}
+	return t
+}
+
+func variantTable(variants []client.ProductVariant) table {
+	t := table{header: []string{"ID", "SKU", "OPTIONS", "PRICE", "STOCK"}}
+	for _, v := range variants {
+		t.rows = append(t.rows, []string{v.ID, v.SKU, formatOptions(v.Options), v.Price.String(), strconv.Itoa(v.Stock)})
+	}
+	return t
+}
+
+func orderTable(orders []client.Order) table {
+	t := table{header: []string{"ID", "STATUS", "ITEMS", "TOTAL", "CREATED"}}
+	for _, o := range orders {
+		t.rows = append(t.rows, []string{o.ID, o.Status, strconv.Itoa(len(o.OrderItems)), o.TotalAmount.String(), o.CreatedAt})
+	}
+	return t
+}
+
+func orderItemTable(items []client.OrderItem) table {
+	t := table{header: []string{"PRODUCT", "NAME", "SKU", "OPTIONS", "QUANTITY", "PRICE", "SUBTOTAL"}}
+	for _, i := range items {
+		t.rows = append(t.rows, []string{
+			i.ProductID, i.Name, i.SKU, formatOptions(i.Options), strconv.Itoa(i.Quantity),
+			i.Price.String(), i.Subtotal.String(),
+		})
+	}
+	return t
+}
+
+// formatOptions lists variant options as name=value pairs in name order
+func formatOptions(options map[string]string) string {
+	pairs := make([]string, 0, len(options))
+	for name, value := range options {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func rateTable(rates []client.ExchangeRate) table {
+	t := table{header: []string{"BASE", "QUOTE", "RATE", "UPDATED"}}
+	for _, r := range rates {
+		t.rows = append(t.rows, []string{r.Base, r.Quote, r.Rate.String(), r.UpdatedAt})
+	}
+	return t
+}
+
+func categoryTable(categories []client.Category) table {
+	t := table{header: []string{"ID", "SLUG", "NAME", "PATH"}}
+	for _, c := range categories {
+		path := make([]string, len(c.Breadcrumbs))
+		for i, crumb := range c.Breadcrumbs {
+			path[i] = crumb.Name
+		}
+		t.rows = append(t.rows, []string{c.ID, c.Slug, c.Name, strings.Join(path, " > ")})
+	}
+	return t
+}
+
+func userTable(users []client.User) table {
+	t := table{header: []string{"ID", "NAME", "EMAIL", "ROLE", "LOCALE", "CREATED"}}
+	for _, u := range users {
+		t.rows = append(t.rows, []string{u.ID, u.Name, u.Email, u.Role, u.Locale, u.CreatedAt})
+	}
+	return t
+}
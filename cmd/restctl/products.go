@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"rest-api/pkg/client"
+)
+
+func runProductsList(ctx context.Context, args []string) error {
+	flags, opts := newFlags("products list", "[--mine | --category SLUG [--descendants]] [--all] [--page N] [--limit N]")
+	mine := flags.Bool("mine", false, "list only your products")
+	category := flags.String("category", "", "list only products in the category with this slug")
+	descendants := flags.Bool("descendants", false, "with --category, include its subcategories")
+	all := flags.Bool("all", false, "list every page")
+	page := flags.Int("page", 1, "page number")
+	limit := flags.Int("limit", 10, "products per page, at most 100")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	if *mine && *category != "" {
+		fmt.Fprintln(os.Stderr, "--mine and --category cannot be combined")
+		return errUsage
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+
+	var products []client.Product
+	if *all {
+		iterate := api.Products
+		if *mine {
+			iterate = api.MyProducts
+		}
+		if *category != "" {
+			iterate = func(ctx context.Context, limit int) iter.Seq2[client.Product, error] {
+				return api.ProductsInCategory(ctx, *category, *descendants, limit)
+			}
+		}
+		for product, err := range iterate(ctx, *limit) {
+			if err != nil {
+				return err
+			}
+			products = append(products, product)
+		}
+	} else {
+		list := api.ListProducts
+		if *mine {
+			list = api.ListMyProducts
+		}
+		if *category != "" {
+			list = func(ctx context.Context, opts *client.ListOptions) (*client.Page[client.Product], error) {
+				return api.ListProductsInCategory(ctx, *category, *descendants, opts)
+			}
+		}
+		result, err := list(ctx, &client.ListOptions{Page: *page, Limit: *limit})
+		if err != nil {
+			return err
+		}
+		products = result.Items
+		if opts.output == formatTable {
+			defer fmt.Fprintf(os.Stderr, "page %d, %d of %d products\n", result.Page, len(result.Items), result.Total)
+		}
+	}
+
+	if products == nil {
+		products = []client.Product{}
+	}
+	return render(opts.output, products, productTable(products))
+}
+
+func runProductsGet(ctx context.Context, args []string) error {
+	flags, opts := newFlags("products get", "ID [--currency CODE]")
+	currency := flags.String("currency", "", "ISO 4217 code to convert the prices into")
+	positional, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	product, err := api.GetProductIn(ctx, positional[0], *currency)
+	if err != nil {
+		return err
+	}
+	if err := render(opts.output, product, productTable([]client.Product{*product})); err != nil {
+		return err
+	}
+	if opts.output == formatTable && len(product.Variants) > 0 {
+		fmt.Println()
+		return render(opts.output, nil, variantTable(product.Variants))
+	}
+	return nil
+}
+
+func runProductsCreate(ctx context.Context, args []string) error {
+	flags, opts := newFlags("products create", "--name NAME --price PRICE --stock N [--currency CODE] [--description TEXT] [--tags A,B]")
+	var req client.CreateProductRequest
+	flags.StringVar(&req.Name, "name", "", "product name (required)")
+	flags.StringVar(&req.Description, "description", "", "product description")
+	flags.TextVar(&req.Price, "price", client.Amount(0), "price, greater than 0 (required)")
+	flags.StringVar(&req.Currency, "currency", "", "ISO 4217 currency code of the price (default USD)")
+	flags.IntVar(&req.Stock, "stock", 0, "units in stock (required)")
+	tags := flags.String("tags", "", "comma-separated tags")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	req.Tags = splitList(*tags)
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	product, err := api.CreateProduct(ctx, &req)
+	if err != nil {
+		return err
+	}
+	return render(opts.output, product, productTable([]client.Product{*product}))
+}
+
+func runProductsUpdate(ctx context.Context, args []string) error {
+	flags, opts := newFlags("products update", "ID [--name NAME] [--description TEXT] [--price PRICE] [--currency CODE] [--stock N] [--tags A,B]")
+	flags.String("name", "", "new name")
+	flags.String("description", "", "new description; empty clears it")
+	flags.TextVar(new(client.Amount), "price", client.Amount(0), "new price")
+	flags.String("currency", "", "new ISO 4217 currency code")
+	flags.Int("stock", 0, "new stock")
+	flags.String("tags", "", "comma-separated tags replacing the current ones; empty clears them")
+	positional, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	// Send only the flags that were given, as a merge patch
+	patch := map[string]interface{}{}
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name", "price", "currency", "stock":
+			patch[f.Name] = f.Value.(flag.Getter).Get()
+		case "description":
+			if description := f.Value.String(); description != "" {
+				patch[f.Name] = description
+			} else {
+				patch[f.Name] = nil
+			}
+		case "tags":
+			tags := splitList(f.Value.String())
+			if tags == nil {
+				tags = []string{}
+			}
+			patch[f.Name] = tags
+		}
+	})
+	if len(patch) == 0 {
+		fmt.Fprintln(os.Stderr, "nothing to update: give at least one of --name, --description, --price, --currency, --stock or --tags")
+		return errUsage
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	product, err := api.PatchProduct(ctx, positional[0], patch)
+	if err != nil {
+		return err
+	}
+	return render(opts.output, product, productTable([]client.Product{*product}))
+}
+
+func runProductsDelete(ctx context.Context, args []string) error {
+	flags, opts := newFlags("products delete", "ID")
+	positional, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	if err := api.DeleteProduct(ctx, positional[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Deleted product %s\n", positional[0])
+	return nil
+}
+
+func runProductsUploadImage(ctx context.Context, args []string) error {
+	flags, opts := newFlags("products upload-image", "ID FILE")
+	positional, err := parse(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(positional[1])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	product, err := api.UploadProductImage(ctx, positional[0], filepath.Base(positional[1]), file)
+	if err != nil {
+		return err
+	}
+	return render(opts.output, product, productTable([]client.Product{*product}))
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"rest-api/pkg/client"
+)
+
+func runRatesList(ctx context.Context, args []string) error {
+	flags, opts := newFlags("rates list", "")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	rates, err := api.ListExchangeRates(ctx)
+	if err != nil {
+		return err
+	}
+	if rates == nil {
+		rates = []client.ExchangeRate{}
+	}
+	return render(opts.output, rates, rateTable(rates))
+}
+
+func runRatesSet(ctx context.Context, args []string) error {
+	flags, opts := newFlags("rates set", "BASE QUOTE RATE")
+	positional, err := parse(flags, args, 3)
+	if err != nil {
+		return err
+	}
+	rate, err := client.ParseRate(positional[2])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid rate %q: want a positive decimal such as 0.92\n", positional[2])
+		return errUsage
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	exchangeRate, err := api.SetExchangeRate(ctx, positional[0], positional[1], rate)
+	if err != nil {
+		return err
+	}
+	return render(opts.output, exchangeRate, rateTable([]client.ExchangeRate{*exchangeRate}))
+}
+
+func runRatesDelete(ctx context.Context, args []string) error {
+	flags, opts := newFlags("rates delete", "BASE QUOTE")
+	positional, err := parse(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	if err := api.DeleteExchangeRate(ctx, positional[0], positional[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Deleted exchange rate %s/%s\n", positional[0], positional[1])
+	return nil
+}
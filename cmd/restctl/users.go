@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+
+	"rest-api/pkg/client"
+)
+
+func runUsersMe(ctx context.Context, args []string) error {
+	flags, opts := newFlags("users me", "")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	user, err := api.Profile(ctx)
+	if err != nil {
+		return err
+	}
+	return render(opts.output, user, userTable([]client.User{*user}))
+}
+
+func runCategoriesList(ctx context.Context, args []string) error {
+	flags, opts := newFlags("categories list", "")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	api, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	categories, err := api.ListCategories(ctx)
+	if err != nil {
+		return err
+	}
+	if categories == nil {
+		categories = []client.Category{}
+	}
+	return render(opts.output, categories, categoryTable(categories))
+}
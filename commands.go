@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	mathrand "math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"rest-api/internal/apperrors"
+	"rest-api/internal/config"
+	"rest-api/internal/models"
+	"rest-api/internal/money"
+)
+
+// withApp connects to the database, runs fn and disconnects, turning the
+// result into an exit code
+func withApp(cfg *config.Config, fn func(ctx context.Context, a *app) error) int {
+	ctx := context.Background()
+
+	a, err := newApp(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	defer a.close(ctx)
+
+	if err := fn(ctx, a); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+// parseFlags parses args into flags, returning false after printing the
+// problem when they are invalid
+func parseFlags(flags *flag.FlagSet, args []string) bool {
+	if err := flags.Parse(args); err != nil {
+		return false
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected argument %q\n", flags.Arg(0))
+		flags.Usage()
+		return false
+	}
+	return true
+}
+
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: rest-api migrate up|down [--steps N]|status")
+		return 2
+	}
+
+	switch args[0] {
+	case "up":
+		if !parseFlags(flag.NewFlagSet("migrate up", flag.ContinueOnError), args[1:]) {
+			return 2
+		}
+		return withApp(cfg, func(ctx context.Context, a *app) error {
+			applied, err := a.migrator.Up(ctx)
+			for _, migration := range applied {
+				fmt.Printf("applied %d: %s\n", migration.Version, migration.Description)
+			}
+			if err == nil && len(applied) == 0 {
+				fmt.Println("no pending migrations")
+			}
+			return err
+		})
+
+	case "down":
+		flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := flags.Int("steps", 1, "number of migrations to revert")
+		if !parseFlags(flags, args[1:]) {
+			return 2
+		}
+		return withApp(cfg, func(ctx context.Context, a *app) error {
+			reverted, err := a.migrator.Down(ctx, *steps)
+			for _, migration := range reverted {
+				fmt.Printf("reverted %d: %s\n", migration.Version, migration.Description)
+			}
+			if err == nil && len(reverted) == 0 {
+				fmt.Println("no applied migrations")
+			}
+			return err
+		})
+
+	case "status":
+		if !parseFlags(flag.NewFlagSet("migrate status", flag.ContinueOnError), args[1:]) {
+			return 2
+		}
+		return withApp(cfg, func(ctx context.Context, a *app) error {
+			statuses, err := a.migrator.Status(ctx)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tSTATUS\tAPPLIED AT\tDESCRIPTION")
+			for _, status := range statuses {
+				state, appliedAt := "pending", "-"
+				if status.Applied {
+					state, appliedAt = "applied", status.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, state, appliedAt, status.Description)
+			}
+			return w.Flush()
+		})
+
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n", args[0])
+		return 2
+	}
+}
+
+func runSeed(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	count := flags.Int("products", 10, "number of products to create")
+	email := flags.String("email", "seed@example.com", "email of the user owning the products")
+	if !parseFlags(flags, args) {
+		return 2
+	}
+
+	return withApp(cfg, func(ctx context.Context, a *app) error {
+		// Reuse the seed user when it already exists
+		password := randomPassword()
+		user, err := a.userService.CreateUser(ctx, &models.CreateUserRequest{
+			Name:     "Seed User",
+			Email:    *email,
+			Password: password,
+		})
+		switch {
+		case err == nil:
+			fmt.Printf("created user %s with password %s\n", user.Email, password)
+		case errors.Is(err, apperrors.ErrConflict):
+			existing, err := a.userRepo.GetByEmail(ctx, *email)
+			if err != nil {
+				return err
+			}
+			user = &models.UserResponse{ID: existing.ID.Hex(), Email: existing.Email}
+		default:
+			return err
+		}
+
+		for i := 1; i <= *count; i++ {
+			_, err := a.productService.CreateProduct(ctx, user.ID, &models.CreateProductRequest{
+				Name:        "Sample Product " + strconv.Itoa(i),
+				Description: "Seeded product for development and testing",
+				Price:       money.FromMinor(int64(100+mathrand.IntN(49900)), money.DefaultCurrency),
+				Stock:       mathrand.IntN(100),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("created %d products for %s\n", *count, user.Email)
+		return nil
+	})
+}
+
+func runCreateAdmin(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "admin email (required)")
+	name := flags.String("name", "Administrator", "admin name")
+	password := flags.String("password", "", "admin password (generated when empty)")
+	if !parseFlags(flags, args) {
+		return 2
+	}
+	if *email == "" {
+		fmt.Fprintln(os.Stderr, "--email is required")
+		return 2
+	}
+
+	generated := *password == ""
+	if generated {
+		*password = randomPassword()
+	}
+
+	return withApp(cfg, func(ctx context.Context, a *app) error {
+		user, created, err := a.userService.CreateAdmin(ctx, &models.CreateUserRequest{
+			Name:     *name,
+			Email:    *email,
+			Password: *password,
+		})
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !created:
+			fmt.Printf("promoted existing user %s (%s) to admin; password unchanged\n", user.Email, user.ID)
+		case generated:
+			fmt.Printf("created admin %s (%s) with password %s\n", user.Email, user.ID, *password)
+		default:
+			fmt.Printf("created admin %s (%s)\n", user.Email, user.ID)
+		}
+		return nil
+	})
+}
+
+func runResetPassword(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	email := flags.String("email", "", "user email (required)")
+	password := flags.String("password", "", "new password (generated when empty)")
+	if !parseFlags(flags, args) {
+		return 2
+	}
+	if *email == "" {
+		fmt.Fprintln(os.Stderr, "--email is required")
+		return 2
+	}
+
+	generated := *password == ""
+	if generated {
+		*password = randomPassword()
+	}
+
+	return withApp(cfg, func(ctx context.Context, a *app) error {
+		if err := a.userService.ResetPassword(ctx, *email, *password); err != nil {
+			return err
+		}
+
+		if generated {
+			fmt.Printf("password for %s reset to %s\n", *email, *password)
+		} else {
+			fmt.Printf("password for %s reset\n", *email)
+		}
+		return nil
+	})
+}
+
+func runPurgeDeleted(cfg *config.Config, args []string) int {
+	if !parseFlags(flag.NewFlagSet("purge-deleted", flag.ContinueOnError), args) {
+		return 2
+	}
+
+	return withApp(cfg, func(ctx context.Context, a *app) error {
+		deleted, err := a.productService.PurgeOrphanedProducts(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("removed %d products owned by deleted users\n", deleted)
+		return nil
+	})
+}
+
+// runImportRates sets the exchange rates listed in a CSV file of base,quote,rate
+// rows, such as "USD,EUR,0.92". A header row naming the columns, blank lines
+// and lines starting with # are skipped; rates not in the file are kept.
+func runImportRates(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("import-rates", flag.ContinueOnError)
+	file := flags.String("file", "", "CSV file of base,quote,rate rows (required)")
+	if !parseFlags(flags, args) {
+		return 2
+	}
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "--file is required")
+		return 2
+	}
+
+	return withApp(cfg, func(ctx context.Context, a *app) error {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		reader := csv.NewReader(f)
+		reader.Comment = '#'
+		reader.FieldsPerRecord = 3
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return err
+		}
+
+		set := 0
+		for i, record := range records {
+			if i == 0 && strings.EqualFold(record[0], "base") {
+				continue
+			}
+			rate, err := money.ParseRate(record[2])
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+			_, err = a.rateService.SetExchangeRate(ctx, record[0], record[1], &models.SetExchangeRateRequest{Rate: rate})
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+			set++
+		}
+
+		fmt.Printf("set %d exchange rates from %s\n", set, *file)
+		return nil
+	})
+}
+
+// randomPassword returns a 22 character URL-safe random password
+func randomPassword() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"rest-api/internal/config"
+)
+
+// runConfigCommand handles "config print", which writes the effective
+// configuration with secrets redacted and then reports validation errors
+func runConfigCommand(cfg *config.Config, args []string) int {
+	if len(args) != 1 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: rest-api config print")
+		return 2
+	}
+
+	for _, setting := range cfg.Settings() {
+		fmt.Printf("%s=%s  # %s\n", setting.Key, setting.Redacted(), setting.Source)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "\ninvalid configuration:\n%v\n", err)
+		return 1
+	}
+	return 0
+}
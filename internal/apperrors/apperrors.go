@@ -0,0 +1,90 @@
+package apperrors
+
+import (
+	"errors"
+
+	"rest-api/internal/i18n"
+)
+
+// Error kinds returned by repositories and services
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrTooLarge     = errors.New("payload too large")
+	ErrUnsupported  = errors.New("unsupported media type")
+	ErrInternal     = errors.New("internal error")
+)
+
+// Error is a domain error of a given kind. Key is an i18n message key that
+// is safe to show to clients; Err is the underlying cause, which is not.
+type Error struct {
+	Kind error
+	Key  string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	message := e.Kind.Error()
+	if e.Key != "" {
+		message = i18n.T(i18n.LocaleEN, e.Key)
+	}
+	if e.Err != nil {
+		return message + ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func BadRequest(key string, err error) error {
+	return &Error{Kind: ErrBadRequest, Key: key, Err: err}
+}
+
+func Unauthorized(key string) error {
+	return &Error{Kind: ErrUnauthorized, Key: key}
+}
+
+func Forbidden(key string) error {
+	return &Error{Kind: ErrForbidden, Key: key}
+}
+
+func NotFound(key string) error {
+	return &Error{Kind: ErrNotFound, Key: key}
+}
+
+func Conflict(key string) error {
+	return &Error{Kind: ErrConflict, Key: key}
+}
+
+func TooLarge(key string) error {
+	return &Error{Kind: ErrTooLarge, Key: key}
+}
+
+// Unsupported is returned for uploads whose content type is not accepted
+func Unsupported(key string, err error) error {
+	return &Error{Kind: ErrUnsupported, Key: key, Err: err}
+}
+
+// Validation wraps validator or JSON decoding errors describing invalid fields
+func Validation(err error) error {
+	return &Error{Kind: ErrValidation, Key: i18n.MsgValidationFailed, Err: err}
+}
+
+func Internal(err error) error {
+	return &Error{Kind: ErrInternal, Key: i18n.MsgInternalServerError, Err: err}
+}
+
+// InvalidID is returned when a path or reference ID is not a valid ObjectID
+func InvalidID(err error) error {
+	return BadRequest(i18n.MsgInvalidID, err)
+}
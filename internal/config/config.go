@@ -1,41 +1,399 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"rest-api/internal/buildinfo"
+	"rest-api/internal/i18n"
+	"rest-api/internal/images"
+	"rest-api/internal/ratelimit"
+	"rest-api/internal/storage"
+	"rest-api/internal/tracing"
 
 	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Environments accepted in APP_ENV
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+const (
+	defaultJWTSecret   = "your-secret-key"
+	minJWTSecretLength = 32
 )
 
+// ServerConfig holds the HTTP server timeouts and the shutdown deadline
+type ServerConfig struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+}
+
+// OpenAPIConfig controls validation against the generated OpenAPI document
+type OpenAPIConfig struct {
+	ValidateRequests  bool
+	ValidateResponses bool
+}
+
+// CORSConfig describes which cross-origin requests browsers may make.
+// AllowedOrigins entries are exact origins ("https://app.example.com"),
+// wildcard subdomain patterns ("https://*.example.com") or "*" for any origin.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// MongoConfig holds the MongoDB connection settings. Empty strings, zero
+// numbers and durations and nil flags leave the driver default, or the value
+// in the URI, in place.
+type MongoConfig struct {
+	URI                    string
+	Database               string
+	AppName                string
+	MinPoolSize            int
+	MaxPoolSize            int
+	MaxConnIdleTime        time.Duration
+	ConnectTimeout         time.Duration
+	ServerSelectionTimeout time.Duration
+	SocketTimeout          time.Duration
+	ReadPreference         string
+	ReadConcern            string
+	WriteConcern           string
+	Journal                bool
+	RetryWrites            *bool
+	RetryReads             *bool
+	TLSCAFile              string
+	TLSCertFile            string
+	TLSKeyFile             string
+	ConnectAttempts        int
+	ConnectBackoff         time.Duration
+	ConnectMaxBackoff      time.Duration
+}
+
 type Config struct {
-	MongoURI   string
-	DBName     string
-	JWTSecret  string
-	JWTExpire  int
-	ServerPort string
+	Environment    string
+	Mongo          MongoConfig
+	JWTSecret      string
+	JWTExpire      int
+	ServerPort     string
+	Server         ServerConfig
+	DefaultLocale  string
+	LogLevel       string
+	LogFormat      string
+	Tracing        tracing.Config
+	TrustedProxies []string
+	CORS           CORSConfig
+	OpenAPI        OpenAPIConfig
+	Storage        storage.Config
+	Images         images.Config
+	AuthRateLimit  ratelimit.Limit
+	APIRateLimit   ratelimit.Limit
+	UserRateLimit  ratelimit.Limit
+	HealthTimeout  time.Duration
+	MigrateOnStart bool
+
+	settings []Setting
+	errs     []error
 }
 
-func LoadConfig() *Config {
-	err := godotenv.Load()
+// LoadConfig reads the configuration from the environment (including .env),
+// *_FILE secret files and the YAML or TOML file named by CONFIG_FILE.
+// Invalid values are reported by Validate rather than replaced by defaults.
+func LoadConfig() (*Config, error) {
+	if err := godotenv.Load(); err != nil {
+		slog.Debug(".env file not found")
+	}
+
+	l, err := newLoader(os.Getenv("CONFIG_FILE"))
 	if err != nil {
-		log.Println("Warning: .env file not found")
+		return nil, err
+	}
+
+	storageBackend := l.string("STORAGE_BACKEND", storage.BackendLocal)
+
+	cfg := &Config{
+		Environment: l.string("APP_ENV", EnvDevelopment),
+		Mongo: MongoConfig{
+			URI:                    l.secret("MONGO_URI", "mongodb://localhost:27017"),
+			Database:               l.string("DB_NAME", "rest_api_db"),
+			AppName:                l.string("MONGO_APP_NAME", ""),
+			MinPoolSize:            l.int("MONGO_MIN_POOL_SIZE", 0),
+			MaxPoolSize:            l.int("MONGO_MAX_POOL_SIZE", 0),
+			MaxConnIdleTime:        l.duration("MONGO_MAX_CONN_IDLE_TIME", 0),
+			ConnectTimeout:         l.duration("MONGO_CONNECT_TIMEOUT", 0),
+			ServerSelectionTimeout: l.duration("MONGO_SERVER_SELECTION_TIMEOUT", 0),
+			SocketTimeout:          l.duration("MONGO_SOCKET_TIMEOUT", 0),
+			ReadPreference:         l.string("MONGO_READ_PREFERENCE", ""),
+			ReadConcern:            l.string("MONGO_READ_CONCERN", ""),
+			WriteConcern:           l.string("MONGO_WRITE_CONCERN", ""),
+			Journal:                l.bool("MONGO_JOURNAL", false),
+			RetryWrites:            l.optionalBool("MONGO_RETRY_WRITES"),
+			RetryReads:             l.optionalBool("MONGO_RETRY_READS"),
+			TLSCAFile:              l.string("MONGO_TLS_CA_FILE", ""),
+			TLSCertFile:            l.string("MONGO_TLS_CERT_FILE", ""),
+			TLSKeyFile:             l.string("MONGO_TLS_KEY_FILE", ""),
+			ConnectAttempts:        l.int("MONGO_CONNECT_ATTEMPTS", 5),
+			ConnectBackoff:         l.duration("MONGO_CONNECT_BACKOFF", time.Second),
+			ConnectMaxBackoff:      l.duration("MONGO_CONNECT_MAX_BACKOFF", 30*time.Second),
+		},
+		JWTSecret:     l.secret("JWT_SECRET", defaultJWTSecret),
+		JWTExpire:     l.int("JWT_EXPIRE_HOURS", 24),
+		ServerPort:    l.string("SERVER_PORT", "8080"),
+		DefaultLocale: l.string("DEFAULT_LOCALE", "en"),
+		LogLevel:      l.string("LOG_LEVEL", "info"),
+		LogFormat:     l.string("LOG_FORMAT", "json"),
+		Server: ServerConfig{
+			ReadTimeout:       l.duration("SERVER_READ_TIMEOUT", 15*time.Second),
+			ReadHeaderTimeout: l.duration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+			WriteTimeout:      l.duration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:       l.duration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+			ShutdownTimeout:   l.duration("SHUTDOWN_TIMEOUT", 20*time.Second),
+		},
+		Tracing: tracing.Config{
+			Exporter:     l.string("TRACING_EXPORTER", "none"),
+			OTLPEndpoint: l.string("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", ""),
+			ServiceName:  l.string("OTEL_SERVICE_NAME", "rest-api"),
+			Version:      buildinfo.Version,
+			SampleRatio:  l.float("TRACING_SAMPLE_RATIO", 1),
+		},
+		TrustedProxies: l.list("TRUSTED_PROXIES", ""),
+		CORS: CORSConfig{
+			AllowedOrigins:   l.list("CORS_ALLOWED_ORIGINS", "*"),
+			AllowedMethods:   l.list("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE"),
+			AllowedHeaders:   l.list("CORS_ALLOWED_HEADERS", "Accept,Accept-Language,Authorization,Cache-Control,Content-Type,X-API-Key,X-Request-ID,X-Requested-With"),
+			ExposedHeaders:   l.list("CORS_EXPOSED_HEADERS", "Content-Language,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,Retry-After,X-Request-ID"),
+			AllowCredentials: l.bool("CORS_ALLOW_CREDENTIALS", false),
+			MaxAge:           l.duration("CORS_MAX_AGE", 12*time.Hour),
+		},
+		OpenAPI: OpenAPIConfig{
+			ValidateRequests:  l.bool("OPENAPI_VALIDATE_REQUESTS", false),
+			ValidateResponses: l.bool("OPENAPI_VALIDATE_RESPONSES", false),
+		},
+		Storage: storage.Config{
+			Backend:   storageBackend,
+			PublicURL: l.string("STORAGE_PUBLIC_URL", storage.DefaultPublicURL(storageBackend)),
+			LocalDir:  l.string("STORAGE_LOCAL_DIR", "./uploads"),
+			S3: storage.S3Config{
+				Bucket:          l.string("S3_BUCKET", ""),
+				Region:          l.string("S3_REGION", ""),
+				Endpoint:        l.string("S3_ENDPOINT", ""),
+				PathStyle:       l.bool("S3_PATH_STYLE", false),
+				AccessKeyID:     l.string("S3_ACCESS_KEY_ID", ""),
+				SecretAccessKey: l.secret("S3_SECRET_ACCESS_KEY", ""),
+			},
+		},
+		Images: images.Config{
+			MaxBytes:      int64(l.int("IMAGE_MAX_BYTES", 5<<20)),
+			MaxPerProduct: l.int("IMAGE_MAX_PER_PRODUCT", 10),
+			MaxPixels:     l.int("IMAGE_MAX_PIXELS", 40_000_000),
+			ThumbnailSize: l.int("IMAGE_THUMBNAIL_SIZE", 320),
+		},
+		AuthRateLimit:  l.limit("AUTH_RATE_LIMIT", "10/1m"),
+		APIRateLimit:   l.limit("API_RATE_LIMIT", "300/1m"),
+		UserRateLimit:  l.limit("USER_RATE_LIMIT", "120/1m"),
+		HealthTimeout:  l.duration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		MigrateOnStart: l.bool("MIGRATE_ON_START", true),
+	}
+
+	for _, key := range l.unknownKeys() {
+		l.errs = append(l.errs, fmt.Errorf("%s: unknown key in config file", key))
+	}
+	cfg.settings = l.settings
+	cfg.errs = l.errs
+
+	return cfg, nil
+}
+
+// IsProduction reports whether the stricter production checks apply
+func (c *Config) IsProduction() bool {
+	return c.Environment == EnvProduction
+}
+
+// Validate reports every invalid or unsafe setting. Outside production a
+// default JWT secret only logs a warning.
+func (c *Config) Validate() error {
+	errs := append([]error(nil), c.errs...)
+	invalid := func(key, reason string) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, reason))
+	}
+
+	switch c.Environment {
+	case EnvDevelopment, EnvTest, EnvStaging, EnvProduction:
+	default:
+		invalid("APP_ENV", "must be development, test, staging or production")
+	}
+
+	switch {
+	case c.JWTSecret == defaultJWTSecret && c.IsProduction():
+		invalid("JWT_SECRET", "the default secret must not be used in production")
+	case len(c.JWTSecret) < minJWTSecretLength && c.IsProduction():
+		invalid("JWT_SECRET", fmt.Sprintf("must be at least %d characters in production", minJWTSecretLength))
+	case c.JWTSecret == "":
+		invalid("JWT_SECRET", "must not be empty")
+	case c.JWTSecret == defaultJWTSecret:
+		slog.Warn("Using the default JWT secret; set JWT_SECRET before deploying")
+	}
+	if c.JWTExpire <= 0 {
+		invalid("JWT_EXPIRE_HOURS", "must be positive")
+	}
+
+	errs = append(errs, c.Mongo.validate()...)
+	if port, err := strconv.Atoi(c.ServerPort); err != nil || port < 1 || port > 65535 {
+		invalid("SERVER_PORT", "must be a port number between 1 and 65535")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		invalid("SHUTDOWN_TIMEOUT", "must be positive")
+	}
+	if c.OpenAPI.ValidateResponses && !c.OpenAPI.ValidateRequests {
+		invalid("OPENAPI_VALIDATE_RESPONSES", "requires OPENAPI_VALIDATE_REQUESTS")
+	}
+	if c.OpenAPI.ValidateResponses && c.IsProduction() {
+		slog.Warn("OpenAPI response validation buffers every response; disable it in production")
+	}
+	if c.HealthTimeout <= 0 {
+		invalid("HEALTH_CHECK_TIMEOUT", "must be positive")
+	}
+
+	switch strings.ToLower(c.Storage.Backend) {
+	case storage.BackendLocal:
+		if c.Storage.LocalDir == "" {
+			invalid("STORAGE_LOCAL_DIR", "must not be empty")
+		}
+		if c.Storage.PublicURL == "" {
+			invalid("STORAGE_PUBLIC_URL", "must not be empty with the local backend")
+		}
+	case storage.BackendS3:
+		if c.Storage.S3.Bucket == "" {
+			invalid("S3_BUCKET", "must not be empty with the s3 backend")
+		}
+		if c.Storage.S3.AccessKeyID != "" && c.Storage.S3.SecretAccessKey == "" {
+			invalid("S3_SECRET_ACCESS_KEY", "is required with S3_ACCESS_KEY_ID")
+		}
+	default:
+		invalid("STORAGE_BACKEND", "must be local or s3")
+	}
+	if c.Images.MaxBytes <= 0 {
+		invalid("IMAGE_MAX_BYTES", "must be positive")
+	}
+	if c.Images.MaxPerProduct < 1 {
+		invalid("IMAGE_MAX_PER_PRODUCT", "must be at least 1")
+	}
+	if c.Images.MaxPixels <= 0 {
+		invalid("IMAGE_MAX_PIXELS", "must be positive")
+	}
+	if c.Images.ThumbnailSize < 16 {
+		invalid("IMAGE_THUMBNAIL_SIZE", "must be at least 16")
+	}
+
+	if !i18n.IsSupported(c.DefaultLocale) {
+		invalid("DEFAULT_LOCALE", "must be a supported locale (en or id)")
+	}
+	switch strings.ToLower(c.LogLevel) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		invalid("LOG_LEVEL", "must be debug, info, warn or error")
+	}
+	switch strings.ToLower(c.LogFormat) {
+	case "json", "text":
+	default:
+		invalid("LOG_FORMAT", "must be json or text")
+	}
+
+	switch strings.ToLower(c.Tracing.Exporter) {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		invalid("TRACING_EXPORTER", "must be none, stdout or otlp")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		invalid("TRACING_SAMPLE_RATIO", "must be between 0 and 1")
 	}
 
-	jwtExpire, _ := strconv.Atoi(getEnv("JWT_EXPIRE_HOURS", "24"))
+	return errors.Join(errs...)
+}
+
+func (m MongoConfig) validate() []error {
+	var errs []error
+	invalid := func(key, reason string) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, reason))
+	}
 
-	return &Config{
-		MongoURI:   getEnv("MONGO_URI", "mongodb://localhost:27017"),
-		DBName:     getEnv("DB_NAME", "rest_api_db"),
-		JWTSecret:  getEnv("JWT_SECRET", "your-secret-key"),
-		JWTExpire:  jwtExpire,
-		ServerPort: getEnv("SERVER_PORT", "8080"),
+	if m.URI == "" {
+		invalid("MONGO_URI", "must not be empty")
+	}
+	if m.Database == "" {
+		invalid("DB_NAME", "must not be empty")
+	}
+	if m.MinPoolSize < 0 || m.MaxPoolSize < 0 {
+		invalid("MONGO_MAX_POOL_SIZE", "pool sizes must not be negative")
+	} else if m.MaxPoolSize > 0 && m.MinPoolSize > m.MaxPoolSize {
+		invalid("MONGO_MIN_POOL_SIZE", "must not exceed MONGO_MAX_POOL_SIZE")
+	}
+	if m.ReadPreference != "" {
+		if _, err := readpref.ModeFromString(m.ReadPreference); err != nil {
+			invalid("MONGO_READ_PREFERENCE", "must be primary, primaryPreferred, secondary, secondaryPreferred or nearest")
+		}
+	}
+	switch m.ReadConcern {
+	case "", "local", "available", "majority", "linearizable", "snapshot":
+	default:
+		invalid("MONGO_READ_CONCERN", "must be local, available, majority, linearizable or snapshot")
+	}
+	if m.WriteConcern != "" && m.WriteConcern != "majority" {
+		if w, err := strconv.Atoi(m.WriteConcern); err != nil || w < 0 {
+			invalid("MONGO_WRITE_CONCERN", "must be majority or a number of nodes")
+		}
+	}
+	if m.TLSKeyFile != "" && m.TLSCertFile == "" {
+		invalid("MONGO_TLS_KEY_FILE", "requires MONGO_TLS_CERT_FILE")
 	}
+	if m.ConnectAttempts < 1 {
+		invalid("MONGO_CONNECT_ATTEMPTS", "must be at least 1")
+	}
+	if m.ConnectBackoff <= 0 {
+		invalid("MONGO_CONNECT_BACKOFF", "must be positive")
+	}
+	if m.ConnectMaxBackoff <= 0 {
+		invalid("MONGO_CONNECT_MAX_BACKOFF", "must be positive")
+	}
+
+	return errs
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// Settings lists every resolved setting with its source, in load order
+func (c *Config) Settings() []Setting {
+	return c.settings
+}
+
+// Redacted returns the value safe for display: secrets are hidden, except
+// that URLs keep everything but their password
+func (s Setting) Redacted() string {
+	if !s.Secret || s.Value == "" {
+		return s.Value
+	}
+
+	if u, err := url.Parse(s.Value); err == nil && u.Scheme != "" && u.Host != "" {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), "REDACTED")
+		}
+		return u.String()
 	}
-	return defaultValue
+	return "[REDACTED]"
 }
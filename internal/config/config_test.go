@@ -0,0 +1,82 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"rest-api/internal/buildinfo"
+)
+
+func TestStoragePublicURLDefaultsPerBackend(t *testing.T) {
+	tests := []struct {
+		backend string
+		want    string
+	}{
+		{"local", "/uploads"},
+		// Empty, so object URLs point at the bucket
+		{"s3", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			t.Setenv("CONFIG_FILE", "")
+			t.Setenv("STORAGE_BACKEND", tt.backend)
+			t.Setenv("STORAGE_PUBLIC_URL", "")
+			t.Setenv("S3_BUCKET", "product-images")
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if cfg.Storage.PublicURL != tt.want {
+				t.Errorf("PublicURL = %q, want %q", cfg.Storage.PublicURL, tt.want)
+			}
+		})
+	}
+}
+
+func TestMongoTimeoutsKeepURIOptions(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("MONGO_URI", "mongodb://localhost:27017/?connectTimeoutMS=2500&serverSelectionTimeoutMS=4000")
+	t.Setenv("MONGO_CONNECT_TIMEOUT", "")
+	t.Setenv("MONGO_SERVER_SELECTION_TIMEOUT", "")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	clientOptions, err := mongoClientOptions(cfg.Mongo)
+	if err != nil {
+		t.Fatalf("mongoClientOptions: %v", err)
+	}
+	if got := *clientOptions.ConnectTimeout; got != 2500*time.Millisecond {
+		t.Errorf("connect timeout = %s, want the URI's 2.5s", got)
+	}
+	if got := *clientOptions.ServerSelectionTimeout; got != 4*time.Second {
+		t.Errorf("server selection timeout = %s, want the URI's 4s", got)
+	}
+	if got := pingTimeout(clientOptions); got != 6500*time.Millisecond {
+		t.Errorf("ping timeout = %s, want 6.5s", got)
+	}
+
+	// Settings that are set take precedence
+	cfg.Mongo.ConnectTimeout = time.Second
+	clientOptions, err = mongoClientOptions(cfg.Mongo)
+	if err != nil {
+		t.Fatalf("mongoClientOptions: %v", err)
+	}
+	if got := *clientOptions.ConnectTimeout; got != time.Second {
+		t.Errorf("connect timeout = %s, want the configured 1s", got)
+	}
+}
+
+func TestTracingVersionIsBuildVersion(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Tracing.Version != buildinfo.Version {
+		t.Errorf("tracing version = %q, want the build version %q", cfg.Tracing.Version, buildinfo.Version)
+	}
+}
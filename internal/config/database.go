@@ -2,36 +2,194 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"strconv"
 	"time"
 
+	"rest-api/internal/tracing"
+
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
-func ConnectDatabase(config *Config) (*mongo.Database, error) {
-	// Set client options
-	clientOptions := options.Client().ApplyURI(config.MongoURI)
+// ConnectDatabase connects to MongoDB, retrying with exponential backoff
+// until the server answers a ping or the attempts run out
+func ConnectDatabase(ctx context.Context, config *Config) (*mongo.Database, error) {
+	clientOptions, err := mongoClientOptions(config.Mongo)
+	if err != nil {
+		return nil, err
+	}
 
-	// Set connection timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	backoff := config.Mongo.ConnectBackoff
+	for attempt := 1; ; attempt++ {
+		client, err := connect(ctx, clientOptions)
+		if err == nil {
+			slog.Info("MongoDB connected successfully", "database", config.Mongo.Database, "attempt", attempt)
+			return client.Database(config.Mongo.Database), nil
+		}
+		if attempt >= config.Mongo.ConnectAttempts {
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
 
-	// Connect to MongoDB
+		slog.Warn("MongoDB connection failed, retrying", "attempt", attempt, "retry_in", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, config.Mongo.ConnectMaxBackoff)
+	}
+}
+
+func connect(ctx context.Context, clientOptions *options.ClientOptions) (*mongo.Client, error) {
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
 
-	// Check the connection
-	err = client.Ping(ctx, nil)
-	if err != nil {
+	// Connect returns before any server is reached, so ping to check the connection
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout(clientOptions))
+	defer cancel()
+
+	if err := client.Ping(pingCtx, readpref.PrimaryPreferred()); err != nil {
+		_ = client.Disconnect(context.Background())
 		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
+	return client, nil
+}
+
+// The driver's timeouts when neither the URI nor the config sets them
+const (
+	driverConnectTimeout         = 30 * time.Second
+	driverServerSelectionTimeout = 30 * time.Second
+)
+
+// pingTimeout allows the startup ping to select a server and connect to it
+// within the timeouts the client was given
+func pingTimeout(clientOptions *options.ClientOptions) time.Duration {
+	connectTimeout, serverSelectionTimeout := driverConnectTimeout, driverServerSelectionTimeout
+	if clientOptions.ConnectTimeout != nil {
+		connectTimeout = *clientOptions.ConnectTimeout
+	}
+	if clientOptions.ServerSelectionTimeout != nil {
+		serverSelectionTimeout = *clientOptions.ServerSelectionTimeout
+	}
+	return connectTimeout + serverSelectionTimeout
+}
 
-	db := client.Database(config.DBName)
+// mongoClientOptions applies the URI and then the tuning settings that are
+// set, which take precedence over the same options given in the URI
+func mongoClientOptions(config MongoConfig) (*options.ClientOptions, error) {
+	clientOptions := options.Client().ApplyURI(config.URI)
+
+	// Trace every command as a child of the calling operation's span
+	clientOptions.SetMonitor(tracing.NewMongoMonitor())
+
+	if config.AppName != "" {
+		clientOptions.SetAppName(config.AppName)
+	}
+	if config.MinPoolSize > 0 {
+		clientOptions.SetMinPoolSize(uint64(config.MinPoolSize))
+	}
+	if config.MaxPoolSize > 0 {
+		clientOptions.SetMaxPoolSize(uint64(config.MaxPoolSize))
+	}
+	if config.RetryWrites != nil {
+		clientOptions.SetRetryWrites(*config.RetryWrites)
+	}
+	if config.RetryReads != nil {
+		clientOptions.SetRetryReads(*config.RetryReads)
+	}
+
+	if config.MaxConnIdleTime > 0 {
+		clientOptions.SetMaxConnIdleTime(config.MaxConnIdleTime)
+	}
+	if config.ConnectTimeout > 0 {
+		clientOptions.SetConnectTimeout(config.ConnectTimeout)
+	}
+	if config.ServerSelectionTimeout > 0 {
+		clientOptions.SetServerSelectionTimeout(config.ServerSelectionTimeout)
+	}
+	if config.SocketTimeout > 0 {
+		clientOptions.SetSocketTimeout(config.SocketTimeout)
+	}
+
+	if config.ReadPreference != "" {
+		mode, err := readpref.ModeFromString(config.ReadPreference)
+		if err != nil {
+			return nil, err
+		}
+		readPreference, err := readpref.New(mode)
+		if err != nil {
+			return nil, err
+		}
+		clientOptions.SetReadPreference(readPreference)
+	}
+	if config.ReadConcern != "" {
+		clientOptions.SetReadConcern(&readconcern.ReadConcern{Level: config.ReadConcern})
+	}
+	if config.WriteConcern != "" || config.Journal {
+		writeConcern := &writeconcern.WriteConcern{}
+		if w, err := strconv.Atoi(config.WriteConcern); err == nil {
+			writeConcern.W = w
+		} else if config.WriteConcern != "" {
+			writeConcern.W = config.WriteConcern
+		}
+		if config.Journal {
+			writeConcern.Journal = &config.Journal
+		}
+		clientOptions.SetWriteConcern(writeConcern)
+	}
+
+	if config.TLSCAFile != "" || config.TLSCertFile != "" {
+		tlsConfig, err := mongoTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		clientOptions.SetTLSConfig(tlsConfig)
+	}
+
+	if err := clientOptions.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid MongoDB options: %w", err)
+	}
+	return clientOptions, nil
+}
+
+// mongoTLSConfig loads the CA bundle and client certificate. Without a
+// separate key file the certificate file must contain the key as well.
+func mongoTLSConfig(config MongoConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if config.TLSCAFile != "" {
+		ca, err := os.ReadFile(config.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MongoDB CA file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no certificates found in MongoDB CA file")
+		}
+	}
+
+	if config.TLSCertFile != "" {
+		keyFile := config.TLSKeyFile
+		if keyFile == "" {
+			keyFile = config.TLSCertFile
+		}
+		certificate, err := tls.LoadX509KeyPair(config.TLSCertFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load MongoDB client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
 
-	log.Println("MongoDB connected successfully")
-	return db, nil
+	return tlsConfig, nil
 }
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"rest-api/internal/ratelimit"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources a setting can be resolved from, in order of precedence
+const (
+	SourceEnv     = "env"
+	SourceEnvFile = "secret file"
+	SourceFile    = "config file"
+	SourceDefault = "default"
+)
+
+// Setting is one resolved configuration value and where it came from
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+	Secret bool
+}
+
+// loader resolves settings by key, collecting parse errors so they can all
+// be reported at once instead of silently falling back to defaults
+type loader struct {
+	file     map[string]string
+	settings []Setting
+	errs     []error
+}
+
+func newLoader(path string) (*loader, error) {
+	l := &loader{file: make(map[string]string)}
+	if path == "" {
+		return l, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q: use .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	flatten("", values, l.file)
+	return l, nil
+}
+
+// flatten turns nested file sections into environment-style keys, so that
+// "jwt: {secret: x}" and "JWT_SECRET=x" name the same setting
+func flatten(prefix string, values map[string]interface{}, out map[string]string) {
+	for key, value := range values {
+		key = strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(key, v, out)
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			out[key] = strings.Join(items, ",")
+		case nil:
+			out[key] = ""
+		default:
+			out[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// lookup resolves key from the environment, a KEY_FILE secret file, the
+// config file or the default, in that order
+func (l *loader) lookup(key, defaultValue string, secret bool) string {
+	value, source := defaultValue, SourceDefault
+
+	if env := os.Getenv(key); env != "" {
+		value, source = env, SourceEnv
+	} else if path := os.Getenv(key + "_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s_FILE: %w", key, err))
+		} else {
+			value, source = strings.TrimSpace(string(data)), SourceEnvFile
+		}
+	} else if fileValue, ok := l.file[key]; ok {
+		value, source = fileValue, SourceFile
+	}
+
+	l.settings = append(l.settings, Setting{Key: key, Value: value, Source: source, Secret: secret})
+	return value
+}
+
+func (l *loader) fail(key, value, expected string) {
+	l.errs = append(l.errs, fmt.Errorf("%s: invalid value %q, expected %s", key, value, expected))
+}
+
+func (l *loader) string(key, defaultValue string) string {
+	return l.lookup(key, defaultValue, false)
+}
+
+func (l *loader) secret(key, defaultValue string) string {
+	return l.lookup(key, defaultValue, true)
+}
+
+func (l *loader) int(key string, defaultValue int) int {
+	raw := l.lookup(key, strconv.Itoa(defaultValue), false)
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		l.fail(key, raw, "an integer")
+		return defaultValue
+	}
+	return value
+}
+
+func (l *loader) float(key string, defaultValue float64) float64 {
+	raw := l.lookup(key, strconv.FormatFloat(defaultValue, 'f', -1, 64), false)
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		l.fail(key, raw, "a number")
+		return defaultValue
+	}
+	return value
+}
+
+func (l *loader) bool(key string, defaultValue bool) bool {
+	raw := l.lookup(key, strconv.FormatBool(defaultValue), false)
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.fail(key, raw, "true or false")
+		return defaultValue
+	}
+	return value
+}
+
+// optionalBool returns nil when key is not set, so the setting can fall
+// back to a value given elsewhere
+func (l *loader) optionalBool(key string) *bool {
+	raw := l.lookup(key, "", false)
+	if raw == "" {
+		return nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.fail(key, raw, "true or false")
+		return nil
+	}
+	return &value
+}
+
+func (l *loader) duration(key string, defaultValue time.Duration) time.Duration {
+	raw := l.lookup(key, defaultValue.String(), false)
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		l.fail(key, raw, "a duration such as 30s or 5m")
+		return defaultValue
+	}
+	return value
+}
+
+func (l *loader) list(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(l.lookup(key, defaultValue, false), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func (l *loader) limit(key, defaultValue string) ratelimit.Limit {
+	raw := l.lookup(key, defaultValue, false)
+	limit, err := ratelimit.ParseLimit(raw)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: %w", key, err))
+		limit, _ = ratelimit.ParseLimit(defaultValue)
+	}
+	return limit
+}
+
+// unknownKeys lists config file keys that no setting consumed, which are
+// almost always typos
+func (l *loader) unknownKeys() []string {
+	known := make(map[string]bool, len(l.settings))
+	for _, setting := range l.settings {
+		known[setting.Key] = true
+	}
+
+	var unknown []string
+	for key := range l.file {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+
+	"rest-api/internal/i18n"
+	"rest-api/internal/models"
+	"rest-api/internal/responder"
+	"rest-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	categoryService *services.CategoryService
+}
+
+func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.categoryService.ListCategories(c.Request.Context())
+	if err != nil {
+		responder.Error(c, i18n.MsgCategoriesRetrieveFail, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgCategoriesRetrieved),
+		Data:    categories,
+	})
+}
+
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	category, err := h.categoryService.GetCategory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		responder.Error(c, i18n.MsgCategoryRetrieveFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgCategoryRetrieved),
+		Data:    category,
+	})
+}
+
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req models.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.BindError(c, err)
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(c.Request.Context(), &req)
+	if err != nil {
+		responder.Error(c, i18n.MsgCategoryCreateFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgCategoryCreated),
+		Data:    category,
+	})
+}
+
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	var req models.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.BindError(c, err)
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		responder.Error(c, i18n.MsgCategoryUpdateFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgCategoryUpdated),
+		Data:    category,
+	})
+}
+
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	if err := h.categoryService.DeleteCategory(c.Request.Context(), c.Param("id")); err != nil {
+		responder.Error(c, i18n.MsgCategoryDeleteFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgCategoryDeleted),
+	})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"rest-api/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// swaggerInitializer replaces the bundled initializer, which loads the
+// Petstore example, with one that loads our document
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "/openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+type DocsHandler struct {
+	spec []byte
+}
+
+func NewDocsHandler(doc *openapi.Document) (*DocsHandler, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &DocsHandler{spec: spec}, nil
+}
+
+// Spec serves the OpenAPI document
+func (h *DocsHandler) Spec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", h.spec)
+}
+
+// UI serves the embedded Swagger UI
+func (h *DocsHandler) UI(c *gin.Context) {
+	path := c.Param("filepath")
+	if path == "/swagger-initializer.js" {
+		c.Data(http.StatusOK, "application/javascript", []byte(swaggerInitializer))
+		return
+	}
+	c.FileFromFS(path, http.FS(swaggerFiles.FS))
+}
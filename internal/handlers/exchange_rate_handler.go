@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"rest-api/internal/i18n"
+	"rest-api/internal/models"
+	"rest-api/internal/responder"
+	"rest-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRateHandler struct {
+	rateService *services.ExchangeRateService
+}
+
+func NewExchangeRateHandler(rateService *services.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{rateService: rateService}
+}
+
+func (h *ExchangeRateHandler) ListExchangeRates(c *gin.Context) {
+	rates, err := h.rateService.ListExchangeRates(c.Request.Context())
+	if err != nil {
+		responder.Error(c, i18n.MsgExchangeRatesRetrieveFail, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgExchangeRatesRetrieved),
+		Data:    rates,
+	})
+}
+
+func (h *ExchangeRateHandler) SetExchangeRate(c *gin.Context) {
+	var req models.SetExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.BindError(c, err)
+		return
+	}
+
+	rate, err := h.rateService.SetExchangeRate(c.Request.Context(), c.Param("base"), c.Param("quote"), &req)
+	if err != nil {
+		responder.Error(c, i18n.MsgExchangeRateSetFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgExchangeRateSet),
+		Data:    rate,
+	})
+}
+
+func (h *ExchangeRateHandler) DeleteExchangeRate(c *gin.Context) {
+	if err := h.rateService.DeleteExchangeRate(c.Request.Context(), c.Param("base"), c.Param("quote")); err != nil {
+		responder.Error(c, i18n.MsgExchangeRateDeleteFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgExchangeRateDeleted),
+	})
+}
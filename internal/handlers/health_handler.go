@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"rest-api/internal/buildinfo"
+	"rest-api/internal/migrations"
+	"rest-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Health statuses reported by the probes
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// Pinger is implemented by *mongo.Client
+type Pinger interface {
+	Ping(ctx context.Context, rp *readpref.ReadPref) error
+}
+
+type HealthHandler struct {
+	db       Pinger
+	migrator *migrations.Migrator
+	timeout  time.Duration
+}
+
+func NewHealthHandler(db Pinger, migrator *migrations.Migrator, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{db: db, migrator: migrator, timeout: timeout}
+}
+
+// Live reports whether the process is running; it never checks dependencies
+// so a slow database cannot get the container restarted
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, models.HealthResponse{
+		Status: HealthStatusUp,
+		Build:  buildinfo.Get(),
+	})
+}
+
+// Ready reports whether the service can handle traffic: MongoDB must answer
+// a ping within the timeout and every migration must have been applied
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	checks := map[string]models.HealthCheck{
+		"mongodb":    h.checkMongo(ctx),
+		"migrations": h.checkMigrations(ctx),
+	}
+
+	status := http.StatusOK
+	response := models.HealthResponse{
+		Status: HealthStatusUp,
+		Checks: checks,
+		Build:  buildinfo.Get(),
+	}
+	for _, check := range checks {
+		if check.Status != HealthStatusUp {
+			status = http.StatusServiceUnavailable
+			response.Status = HealthStatusDown
+		}
+	}
+
+	c.JSON(status, response)
+}
+
+func (h *HealthHandler) checkMongo(ctx context.Context) models.HealthCheck {
+	start := time.Now()
+	err := h.db.Ping(ctx, readpref.Primary())
+	return newHealthCheck(start, err, nil)
+}
+
+func (h *HealthHandler) checkMigrations(ctx context.Context) models.HealthCheck {
+	start := time.Now()
+	statuses, err := h.migrator.Status(ctx)
+	if err != nil {
+		return newHealthCheck(start, err, nil)
+	}
+
+	var pending []int64
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status.Version)
+		}
+	}
+
+	check := newHealthCheck(start, nil, gin.H{
+		"applied": len(statuses) - len(pending),
+		"pending": pending,
+	})
+	if len(pending) > 0 {
+		check.Status = HealthStatusDown
+		check.Error = "pending migrations"
+	}
+	return check
+}
+
+func newHealthCheck(start time.Time, err error, details interface{}) models.HealthCheck {
+	check := models.HealthCheck{
+		Status:    HealthStatusUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   details,
+	}
+	if err != nil {
+		check.Status = HealthStatusDown
+		check.Error = err.Error()
+	}
+	return check
+}
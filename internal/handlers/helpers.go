@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/models"
+	"rest-api/internal/money"
+	"rest-api/internal/responder"
+	"rest-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localize translates a message key into the locale negotiated for the request
+func localize(c *gin.Context, key string) string {
+	return i18n.T(c.GetString("locale"), key)
+}
+
+// readMergePatch reads an RFC 7396 merge patch document from the request body
+func readMergePatch(c *gin.Context) ([]byte, bool) {
+	contentType := c.ContentType()
+	if contentType != utils.MergePatchContentType && contentType != "application/json" {
+		responder.Fail(c, http.StatusUnsupportedMediaType, models.ErrCodeUnsupportedMediaType, i18n.MsgUnsupportedContentType)
+		return nil, false
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil || len(patch) == 0 {
+		responder.Fail(c, http.StatusBadRequest, models.ErrCodeInvalidRequestBody, i18n.MsgInvalidRequestBody)
+		return nil, false
+	}
+
+	return patch, true
+}
+
+// queryAmount parses an optional decimal amount query parameter, responding
+// with 400 when it is malformed
+func queryAmount(c *gin.Context, name string) (*money.Amount, bool) {
+	raw, ok := c.GetQuery(name)
+	if !ok || raw == "" {
+		return nil, true
+	}
+	value, err := money.Parse(raw)
+	if err != nil {
+		responder.Error(c, i18n.MsgInvalidParameters, apperrors.BadRequest(i18n.MsgInvalidParameters, err))
+		return nil, false
+	}
+	return &value, true
+}
+
+// queryBool parses an optional boolean query parameter, responding with 400
+// when it is malformed
+func queryBool(c *gin.Context, name string) (*bool, bool) {
+	raw, ok := c.GetQuery(name)
+	if !ok || raw == "" {
+		return nil, true
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		responder.Error(c, i18n.MsgInvalidParameters, apperrors.BadRequest(i18n.MsgInvalidParameters, err))
+		return nil, false
+	}
+	return &value, true
+}
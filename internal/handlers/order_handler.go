@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/models"
+	"rest-api/internal/responder"
+	"rest-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrderHandler struct {
+	orderService *services.OrderService
+}
+
+func NewOrderHandler(orderService *services.OrderService) *OrderHandler {
+	return &OrderHandler{orderService: orderService}
+}
+
+func (h *OrderHandler) PlaceOrder(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
+		return
+	}
+
+	var req models.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.BindError(c, err)
+		return
+	}
+
+	order, err := h.orderService.PlaceOrder(c.Request.Context(), userID.(string), c.Query("currency"), &req)
+	if err != nil {
+		responder.Error(c, i18n.MsgOrderPlaceFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgOrderPlaced),
+		Data:    order,
+	})
+}
+
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		responder.Error(c, i18n.MsgOrderRetrieveFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgOrderRetrieved),
+		Data:    order,
+	})
+}
+
+func (h *OrderHandler) GetMyOrders(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	response, err := h.orderService.GetOrdersByUserID(c.Request.Context(), userID.(string), page, limit)
+	if err != nil {
+		responder.Error(c, i18n.MsgOrdersRetrieveFail, err)
+		return
+	}
+
+	response.Message = localize(c, i18n.MsgOrdersRetrieved)
+	c.JSON(http.StatusOK, response)
+}
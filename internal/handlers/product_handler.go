@@ -1,56 +1,55 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
 	"rest-api/internal/models"
+	"rest-api/internal/responder"
 	"rest-api/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
+// multipartOverhead is allowed on top of the image size for the multipart
+// boundaries and part headers
+const multipartOverhead = 64 << 10
+
 type ProductHandler struct {
 	productService *services.ProductService
+	maxImageBytes  int64
 }
 
-func NewProductHandler(productService *services.ProductService) *ProductHandler {
-	return &ProductHandler{productService: productService}
+func NewProductHandler(productService *services.ProductService, maxImageBytes int64) *ProductHandler {
+	return &ProductHandler{productService: productService, maxImageBytes: maxImageBytes}
 }
 
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.APIResponse{
-			Success: false,
-			Message: "User not authenticated",
-		})
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
 		return
 	}
 
 	var req models.CreateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Invalid request body",
-			Error:   err.Error(),
-		})
+		responder.BindError(c, err)
 		return
 	}
 
-	product, err := h.productService.CreateProduct(userID.(string), &req)
+	product, err := h.productService.CreateProduct(c.Request.Context(), userID.(string), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Failed to create product",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgProductCreateFailed, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, models.APIResponse{
 		Success: true,
-		Message: "Product created successfully",
+		Message: localize(c, i18n.MsgProductCreated),
 		Data:    product,
 	})
 }
@@ -58,19 +57,15 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 func (h *ProductHandler) GetProduct(c *gin.Context) {
 	id := c.Param("id")
 
-	product, err := h.productService.GetProductByID(id)
+	product, err := h.productService.GetProductByID(c.Request.Context(), id, c.Query("currency"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.APIResponse{
-			Success: false,
-			Message: "Product not found",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgProductRetrieveFailed, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Product retrieved successfully",
+		Message: localize(c, i18n.MsgProductRetrieved),
 		Data:    product,
 	})
 }
@@ -78,53 +73,60 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	includeDescendants, _ := strconv.ParseBool(c.DefaultQuery("include_descendants", "false"))
+	filter := services.ProductFilter{
+		Category:           c.Query("category"),
+		IncludeDescendants: includeDescendants,
+		Currency:           c.Query("currency"),
+	}
+	if tags := c.Query("tags"); tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+	var ok bool
+	if filter.MinPrice, ok = queryAmount(c, "min_price"); !ok {
+		return
+	}
+	if filter.MaxPrice, ok = queryAmount(c, "max_price"); !ok {
+		return
+	}
+	if filter.InStock, ok = queryBool(c, "in_stock"); !ok {
+		return
+	}
 
-	response, err := h.productService.GetAllProducts(page, limit)
+	response, err := h.productService.GetAllProducts(c.Request.Context(), filter, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Message: "Failed to retrieve products",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgProductsRetrieveFail, err)
 		return
 	}
 
+	response.Message = localize(c, i18n.MsgProductsRetrieved)
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *ProductHandler) GetMyProducts(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.APIResponse{
-			Success: false,
-			Message: "User not authenticated",
-		})
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
 		return
 	}
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	response, err := h.productService.GetProductsByUserID(userID.(string), page, limit)
+	response, err := h.productService.GetProductsByUserID(c.Request.Context(), userID.(string), c.Query("currency"), page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Message: "Failed to retrieve products",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgProductsRetrieveFail, err)
 		return
 	}
 
+	response.Message = localize(c, i18n.MsgProductsRetrieved)
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.APIResponse{
-			Success: false,
-			Message: "User not authenticated",
-		})
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
 		return
 	}
 
@@ -132,27 +134,46 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 
 	var req models.UpdateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Invalid request body",
-			Error:   err.Error(),
-		})
+		responder.BindError(c, err)
+		return
+	}
+
+	product, err := h.productService.UpdateProduct(c.Request.Context(), id, userID.(string), &req)
+	if err != nil {
+		responder.Error(c, i18n.MsgProductUpdateFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgProductUpdated),
+		Data:    product,
+	})
+}
+
+func (h *ProductHandler) PatchProduct(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
+		return
+	}
+
+	id := c.Param("id")
+
+	patch, ok := readMergePatch(c)
+	if !ok {
 		return
 	}
 
-	product, err := h.productService.UpdateProduct(id, userID.(string), &req)
+	product, err := h.productService.PatchProduct(c.Request.Context(), id, userID.(string), patch)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Failed to update product",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgProductUpdateFailed, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Product updated successfully",
+		Message: localize(c, i18n.MsgProductUpdated),
 		Data:    product,
 	})
 }
@@ -160,27 +181,129 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.APIResponse{
-			Success: false,
-			Message: "User not authenticated",
-		})
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
 		return
 	}
 
 	id := c.Param("id")
 
-	err := h.productService.DeleteProduct(id, userID.(string))
+	err := h.productService.DeleteProduct(c.Request.Context(), id, userID.(string))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Failed to delete product",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgProductDeleteFailed, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Product deleted successfully",
+		Message: localize(c, i18n.MsgProductDeleted),
+	})
+}
+
+func (h *ProductHandler) UploadProductImage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxImageBytes+multipartOverhead)
+	header, err := c.FormFile("image")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			responder.Error(c, i18n.MsgProductImageUploadFailed, apperrors.TooLarge(i18n.MsgImageTooLarge))
+			return
+		}
+		responder.Error(c, i18n.MsgProductImageUploadFailed, apperrors.BadRequest(i18n.MsgProductImageRequired, err))
+		return
+	}
+	if header.Size > h.maxImageBytes {
+		responder.Error(c, i18n.MsgProductImageUploadFailed, apperrors.TooLarge(i18n.MsgImageTooLarge))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		responder.Error(c, i18n.MsgProductImageUploadFailed, err)
+		return
+	}
+	defer file.Close()
+
+	product, err := h.productService.UploadProductImage(c.Request.Context(), c.Param("id"), userID.(string), file)
+	if err != nil {
+		responder.Error(c, i18n.MsgProductImageUploadFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgProductImageUploaded),
+		Data:    product,
+	})
+}
+
+func (h *ProductHandler) ReorderProductImages(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
+		return
+	}
+
+	var req models.ReorderProductImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responder.BindError(c, err)
+		return
+	}
+
+	product, err := h.productService.ReorderProductImages(c.Request.Context(), c.Param("id"), userID.(string), &req)
+	if err != nil {
+		responder.Error(c, i18n.MsgProductImagesUpdateFail, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgProductImagesUpdated),
+		Data:    product,
+	})
+}
+
+func (h *ProductHandler) SetPrimaryProductImage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
+		return
+	}
+
+	product, err := h.productService.SetPrimaryProductImage(c.Request.Context(), c.Param("id"), userID.(string), c.Param("image_id"))
+	if err != nil {
+		responder.Error(c, i18n.MsgProductImagesUpdateFail, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgProductImagesUpdated),
+		Data:    product,
+	})
+}
+
+func (h *ProductHandler) DeleteProductImage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
+		return
+	}
+
+	product, err := h.productService.DeleteProductImage(c.Request.Context(), c.Param("id"), userID.(string), c.Param("image_id"))
+	if err != nil {
+		responder.Error(c, i18n.MsgProductImageDeleteFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgProductImageDeleted),
+		Data:    product,
 	})
 }
@@ -4,7 +4,10 @@ import (
 	"net/http"
 	"strconv"
 
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
 	"rest-api/internal/models"
+	"rest-api/internal/responder"
 	"rest-api/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -21,27 +24,19 @@ func NewUserHandler(userService *services.UserService) *UserHandler {
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req models.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Invalid request body",
-			Error:   err.Error(),
-		})
+		responder.BindError(c, err)
 		return
 	}
 
-	user, err := h.userService.CreateUser(&req)
+	user, err := h.userService.CreateUser(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Failed to create user",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgUserCreateFailed, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, models.APIResponse{
 		Success: true,
-		Message: "User created successfully",
+		Message: localize(c, i18n.MsgUserCreated),
 		Data:    user,
 	})
 }
@@ -49,11 +44,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 func (h *UserHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Invalid request body",
-			Error:   err.Error(),
-		})
+		responder.BindError(c, err)
 		return
 	}
 
@@ -61,19 +52,15 @@ func (h *UserHandler) Login(c *gin.Context) {
 	jwtSecret := c.GetString("jwt_secret")
 	jwtExpire := c.GetInt("jwt_expire")
 
-	loginResponse, err := h.userService.Login(&req, jwtSecret, jwtExpire)
+	loginResponse, err := h.userService.Login(c.Request.Context(), &req, jwtSecret, jwtExpire)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.APIResponse{
-			Success: false,
-			Message: "Login failed",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgLoginFailed, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Login successful",
+		Message: localize(c, i18n.MsgLoginSuccessful),
 		Data:    loginResponse,
 	})
 }
@@ -81,26 +68,19 @@ func (h *UserHandler) Login(c *gin.Context) {
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.APIResponse{
-			Success: false,
-			Message: "User not authenticated",
-		})
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
 		return
 	}
 
-	user, err := h.userService.GetUserByID(userID.(string))
+	user, err := h.userService.GetUserByID(c.Request.Context(), userID.(string))
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.APIResponse{
-			Success: false,
-			Message: "User not found",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgProfileRetrieveFailed, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "User profile retrieved successfully",
+		Message: localize(c, i18n.MsgProfileRetrieved),
 		Data:    user,
 	})
 }
@@ -108,36 +88,50 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.APIResponse{
-			Success: false,
-			Message: "User not authenticated",
-		})
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
 		return
 	}
 
 	var req models.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Invalid request body",
-			Error:   err.Error(),
-		})
+		responder.BindError(c, err)
 		return
 	}
 
-	user, err := h.userService.UpdateUser(userID.(string), &req)
+	user, err := h.userService.UpdateUser(c.Request.Context(), userID.(string), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Failed to update user",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgUserUpdateFailed, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "User updated successfully",
+		Message: localize(c, i18n.MsgUserUpdated),
+		Data:    user,
+	})
+}
+
+func (h *UserHandler) PatchProfile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
+		return
+	}
+
+	patch, ok := readMergePatch(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.PatchUser(c.Request.Context(), userID.(string), patch)
+	if err != nil {
+		responder.Error(c, i18n.MsgUserUpdateFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: localize(c, i18n.MsgUserUpdated),
 		Data:    user,
 	})
 }
@@ -146,41 +140,31 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	response, err := h.userService.GetAllUsers(page, limit)
+	response, err := h.userService.GetAllUsers(c.Request.Context(), page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Message: "Failed to retrieve users",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgUsersRetrieveFail, err)
 		return
 	}
 
+	response.Message = localize(c, i18n.MsgUsersRetrieved)
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, models.APIResponse{
-			Success: false,
-			Message: "User not authenticated",
-		})
+		responder.Error(c, i18n.MsgNotAuthenticated, apperrors.ErrUnauthorized)
 		return
 	}
 
-	err := h.userService.DeleteUser(userID.(string))
+	err := h.userService.DeleteUser(c.Request.Context(), userID.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Message: "Failed to delete user",
-			Error:   err.Error(),
-		})
+		responder.Error(c, i18n.MsgUserDeleteFailed, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "User deleted successfully",
+		Message: localize(c, i18n.MsgUserDeleted),
 	})
 }
@@ -0,0 +1,108 @@
+package i18n
+
+import (
+	"fmt"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+	"golang.org/x/text/language"
+)
+
+// Supported locales
+const (
+	LocaleEN = "en"
+	LocaleID = "id"
+)
+
+var supportedLocales = []string{LocaleEN, LocaleID}
+
+var matcher = language.NewMatcher([]language.Tag{language.English, language.Indonesian})
+
+var uni = ut.New(en.New(), en.New(), id.New())
+
+// IsSupported reports whether locale has a message catalog
+func IsSupported(locale string) bool {
+	_, ok := catalog[locale]
+	return ok
+}
+
+// Negotiate picks the best supported locale for an Accept-Language header
+func Negotiate(acceptLanguage, fallback string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return fallback
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return fallback
+	}
+	return supportedLocales[index]
+}
+
+// T returns the message for key in the given locale, falling back to English
+func T(locale, key string, args ...interface{}) string {
+	message, ok := catalog[locale][key]
+	if !ok {
+		message, ok = catalog[LocaleEN][key]
+		if !ok {
+			message = key
+		}
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// Translator returns the validation translator for locale
+func Translator(locale string) ut.Translator {
+	trans, _ := uni.GetTranslator(locale)
+	return trans
+}
+
+// customTranslations are the messages for validation tags registered by
+// this service, per locale
+var customTranslations = map[string]map[string]string{
+	LocaleEN: {
+		"mongodb": "{0} must be a valid ID",
+		"slug":    "{0} may only contain lowercase letters, digits and single hyphens",
+	},
+	LocaleID: {
+		"mongodb": "{0} harus berupa ID yang valid",
+		"slug":    "{0} hanya boleh berisi huruf kecil, angka, dan tanda hubung tunggal",
+	},
+}
+
+// RegisterValidationTranslations registers the built-in validator messages
+// and those of the custom tags for every supported locale
+func RegisterValidationTranslations(validate *validator.Validate) error {
+	if err := en_translations.RegisterDefaultTranslations(validate, Translator(LocaleEN)); err != nil {
+		return err
+	}
+	if err := id_translations.RegisterDefaultTranslations(validate, Translator(LocaleID)); err != nil {
+		return err
+	}
+
+	for locale, messages := range customTranslations {
+		trans := Translator(locale)
+		for tag, message := range messages {
+			register := func(trans ut.Translator) error {
+				return trans.Add(tag, message, true)
+			}
+			translate := func(trans ut.Translator, fe validator.FieldError) string {
+				text, _ := trans.T(fe.Tag(), fe.Field())
+				return text
+			}
+			if err := validate.RegisterTranslation(tag, trans, register, translate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
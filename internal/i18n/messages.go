@@ -0,0 +1,398 @@
+package i18n
+
+// Message keys
+const (
+	MsgNotAuthenticated       = "auth.not_authenticated"
+	MsgAuthHeaderRequired     = "auth.header_required"
+	MsgInvalidAuthHeader      = "auth.invalid_header"
+	MsgInvalidToken           = "auth.invalid_token"
+	MsgTokenExpired           = "auth.token_expired"
+	MsgInvalidRequestBody     = "request.invalid_body"
+	MsgUnsupportedContentType = "request.unsupported_content_type"
+	MsgValidationFailed       = "validation.failed"
+	MsgValidationType         = "validation.type"
+	MsgInternalServerError    = "server.internal_error"
+	MsgInvalidID              = "request.invalid_id"
+	MsgInvalidMergePatch      = "request.invalid_merge_patch"
+	MsgRateLimited            = "request.rate_limited"
+	MsgInvalidParameters      = "request.invalid_parameters"
+
+	// Messages for OpenAPI schema violations; the arguments are the field
+	// and the rule's parameter
+	MsgSchemaRequired         = "validation.schema.required"
+	MsgSchemaType             = "validation.schema.type"
+	MsgSchemaEnum             = "validation.schema.enum"
+	MsgSchemaPattern          = "validation.schema.pattern"
+	MsgSchemaFormat           = "validation.schema.format"
+	MsgSchemaMinimum          = "validation.schema.minimum"
+	MsgSchemaMaximum          = "validation.schema.maximum"
+	MsgSchemaExclusiveMinimum = "validation.schema.exclusive_minimum"
+	MsgSchemaExclusiveMaximum = "validation.schema.exclusive_maximum"
+	MsgSchemaMinLength        = "validation.schema.min_length"
+	MsgSchemaMaxLength        = "validation.schema.max_length"
+	MsgSchemaMinItems         = "validation.schema.min_items"
+	MsgSchemaMaxItems         = "validation.schema.max_items"
+	MsgSchemaOneOf            = "validation.schema.one_of"
+
+	MsgUserCreated           = "user.created"
+	MsgUserCreateFailed      = "user.create_failed"
+	MsgLoginSuccessful       = "user.login_successful"
+	MsgLoginFailed           = "user.login_failed"
+	MsgUserNotFound          = "user.not_found"
+	MsgProfileRetrieved      = "user.profile_retrieved"
+	MsgUserUpdated           = "user.updated"
+	MsgUserUpdateFailed      = "user.update_failed"
+	MsgUsersRetrieved        = "user.list_retrieved"
+	MsgUsersRetrieveFail     = "user.list_failed"
+	MsgUserDeleted           = "user.deleted"
+	MsgUserDeleteFailed      = "user.delete_failed"
+	MsgProfileRetrieveFailed = "user.profile_failed"
+	MsgEmailExists           = "user.email_exists"
+	MsgEmailTaken            = "user.email_taken"
+	MsgInvalidCredentials    = "user.invalid_credentials"
+
+	MsgProductCreated         = "product.created"
+	MsgProductCreateFailed    = "product.create_failed"
+	MsgProductNotFound        = "product.not_found"
+	MsgProductRetrieved       = "product.retrieved"
+	MsgProductsRetrieved      = "product.list_retrieved"
+	MsgProductsRetrieveFail   = "product.list_failed"
+	MsgProductUpdated         = "product.updated"
+	MsgProductUpdateFailed    = "product.update_failed"
+	MsgProductDeleted         = "product.deleted"
+	MsgProductDeleteFailed    = "product.delete_failed"
+	MsgProductRetrieveFailed  = "product.retrieve_failed"
+	MsgProductUpdateForbidden = "product.update_forbidden"
+	MsgProductDeleteForbidden = "product.delete_forbidden"
+	MsgProductModified        = "product.modified"
+	MsgUnknownCategory        = "product.unknown_category"
+	MsgPricePrecision         = "product.price_precision"
+	MsgPriceFilterCurrency    = "product.price_filter_currency"
+
+	MsgCategoryCreated        = "category.created"
+	MsgCategoryCreateFailed   = "category.create_failed"
+	MsgCategoryNotFound       = "category.not_found"
+	MsgCategoryRetrieved      = "category.retrieved"
+	MsgCategoryRetrieveFailed = "category.retrieve_failed"
+	MsgCategoriesRetrieved    = "category.list_retrieved"
+	MsgCategoriesRetrieveFail = "category.list_failed"
+	MsgCategoryUpdated        = "category.updated"
+	MsgCategoryUpdateFailed   = "category.update_failed"
+	MsgCategoryDeleted        = "category.deleted"
+	MsgCategoryDeleteFailed   = "category.delete_failed"
+	MsgCategorySlugTaken      = "category.slug_taken"
+	MsgCategoryHasChildren    = "category.has_children"
+	MsgCategoryCycle          = "category.cycle"
+
+	MsgAdminRequired = "auth.admin_required"
+
+	MsgProductImageUploaded     = "product_image.uploaded"
+	MsgProductImageUploadFailed = "product_image.upload_failed"
+	MsgProductImagesUpdated     = "product_image.updated"
+	MsgProductImagesUpdateFail  = "product_image.update_failed"
+	MsgProductImageDeleted      = "product_image.deleted"
+	MsgProductImageDeleteFailed = "product_image.delete_failed"
+	MsgProductImageNotFound     = "product_image.not_found"
+	MsgProductImageRequired     = "product_image.required"
+	MsgProductImageLimit        = "product_image.limit"
+	MsgProductImageOrder        = "product_image.order_mismatch"
+	MsgImageTooLarge            = "product_image.too_large"
+	MsgImageUnsupportedType     = "product_image.unsupported_type"
+	MsgImageInvalid             = "product_image.invalid"
+	MsgImageTooManyPixels       = "product_image.too_many_pixels"
+
+	MsgVariantsWithoutOptions = "product_variant.options_mismatch"
+	MsgDuplicateOption        = "product_variant.duplicate_option"
+	MsgVariantOptions         = "product_variant.invalid_options"
+	MsgDuplicateVariant       = "product_variant.duplicate"
+	MsgDuplicateSKU           = "product_variant.duplicate_sku"
+	MsgSKUTaken               = "product_variant.sku_taken"
+	MsgUnknownVariant         = "product_variant.unknown"
+
+	MsgOrderPlaced         = "order.placed"
+	MsgOrderPlaceFailed    = "order.place_failed"
+	MsgOrderNotFound       = "order.not_found"
+	MsgOrderRetrieved      = "order.retrieved"
+	MsgOrderRetrieveFailed = "order.retrieve_failed"
+	MsgOrdersRetrieved     = "order.list_retrieved"
+	MsgOrdersRetrieveFail  = "order.list_failed"
+	MsgVariantRequired     = "order.variant_required"
+	MsgInsufficientStock   = "order.insufficient_stock"
+	MsgMixedCurrencies     = "order.mixed_currencies"
+	MsgOrderTotalRange     = "order.total_out_of_range"
+
+	MsgExchangeRatesRetrieved    = "exchange_rate.list_retrieved"
+	MsgExchangeRatesRetrieveFail = "exchange_rate.list_failed"
+	MsgExchangeRateSet           = "exchange_rate.set"
+	MsgExchangeRateSetFailed     = "exchange_rate.set_failed"
+	MsgExchangeRateDeleted       = "exchange_rate.deleted"
+	MsgExchangeRateDeleteFailed  = "exchange_rate.delete_failed"
+	MsgExchangeRateNotFound      = "exchange_rate.not_found"
+	MsgExchangeRateUnavailable   = "exchange_rate.unavailable"
+	MsgInvalidCurrency           = "exchange_rate.invalid_currency"
+	MsgSameCurrency              = "exchange_rate.same_currency"
+	MsgConversionOutOfRange      = "exchange_rate.out_of_range"
+)
+
+var catalog = map[string]map[string]string{
+	LocaleEN: {
+		MsgNotAuthenticated:       "User not authenticated",
+		MsgAuthHeaderRequired:     "Authorization header is required",
+		MsgInvalidAuthHeader:      "Invalid authorization header format",
+		MsgInvalidToken:           "Invalid token",
+		MsgTokenExpired:           "token has expired",
+		MsgInvalidRequestBody:     "Invalid request body",
+		MsgUnsupportedContentType: "Unsupported content type",
+		MsgValidationFailed:       "validation failed",
+		MsgValidationType:         "%s must be of type %s",
+		MsgInternalServerError:    "Internal server error",
+		MsgInvalidID:              "Invalid ID format",
+		MsgInvalidMergePatch:      "Invalid merge patch document",
+		MsgRateLimited:            "Too many requests, please try again later",
+		MsgInvalidParameters:      "Invalid request parameters",
+
+		MsgSchemaRequired:         "%[1]s is required",
+		MsgSchemaType:             "%[1]s must be of type %[2]s",
+		MsgSchemaEnum:             "%[1]s must be one of [%[2]s]",
+		MsgSchemaPattern:          "%[1]s has an invalid format",
+		MsgSchemaFormat:           "%[1]s must be a valid %[2]s",
+		MsgSchemaMinimum:          "%[1]s must be %[2]s or greater",
+		MsgSchemaMaximum:          "%[1]s must be %[2]s or less",
+		MsgSchemaExclusiveMinimum: "%[1]s must be greater than %[2]s",
+		MsgSchemaExclusiveMaximum: "%[1]s must be less than %[2]s",
+		MsgSchemaMinLength:        "%[1]s must be at least %[2]s characters long",
+		MsgSchemaMaxLength:        "%[1]s must be at most %[2]s characters long",
+		MsgSchemaMinItems:         "%[1]s must contain at least %[2]s items",
+		MsgSchemaMaxItems:         "%[1]s must contain at most %[2]s items",
+		MsgSchemaOneOf:            "%[1]s does not match any allowed value",
+
+		MsgUserCreated:           "User created successfully",
+		MsgUserCreateFailed:      "Failed to create user",
+		MsgLoginSuccessful:       "Login successful",
+		MsgLoginFailed:           "Login failed",
+		MsgUserNotFound:          "User not found",
+		MsgProfileRetrieved:      "User profile retrieved successfully",
+		MsgUserUpdated:           "User updated successfully",
+		MsgUserUpdateFailed:      "Failed to update user",
+		MsgUsersRetrieved:        "Users retrieved successfully",
+		MsgUsersRetrieveFail:     "Failed to retrieve users",
+		MsgUserDeleted:           "User deleted successfully",
+		MsgUserDeleteFailed:      "Failed to delete user",
+		MsgProfileRetrieveFailed: "Failed to retrieve user profile",
+		MsgEmailExists:           "user with this email already exists",
+		MsgEmailTaken:            "email is already taken",
+		MsgInvalidCredentials:    "invalid email or password",
+
+		MsgProductCreated:         "Product created successfully",
+		MsgProductCreateFailed:    "Failed to create product",
+		MsgProductNotFound:        "Product not found",
+		MsgProductRetrieved:       "Product retrieved successfully",
+		MsgProductsRetrieved:      "Products retrieved successfully",
+		MsgProductsRetrieveFail:   "Failed to retrieve products",
+		MsgProductUpdated:         "Product updated successfully",
+		MsgProductUpdateFailed:    "Failed to update product",
+		MsgProductDeleted:         "Product deleted successfully",
+		MsgProductDeleteFailed:    "Failed to delete product",
+		MsgProductRetrieveFailed:  "Failed to retrieve product",
+		MsgProductUpdateForbidden: "you can only update your own products",
+		MsgProductDeleteForbidden: "you can only delete your own products",
+		MsgProductModified:        "product changed while it was being updated; fetch it and try again",
+		MsgUnknownCategory:        "one or more categories do not exist",
+		MsgPricePrecision:         "prices cannot have more decimal places than their currency",
+		MsgPriceFilterCurrency:    "min_price and max_price require currency",
+
+		MsgCategoryCreated:        "Category created successfully",
+		MsgCategoryCreateFailed:   "Failed to create category",
+		MsgCategoryNotFound:       "Category not found",
+		MsgCategoryRetrieved:      "Category retrieved successfully",
+		MsgCategoryRetrieveFailed: "Failed to retrieve category",
+		MsgCategoriesRetrieved:    "Categories retrieved successfully",
+		MsgCategoriesRetrieveFail: "Failed to retrieve categories",
+		MsgCategoryUpdated:        "Category updated successfully",
+		MsgCategoryUpdateFailed:   "Failed to update category",
+		MsgCategoryDeleted:        "Category deleted successfully",
+		MsgCategoryDeleteFailed:   "Failed to delete category",
+		MsgCategorySlugTaken:      "category slug is already taken",
+		MsgCategoryHasChildren:    "category has subcategories; move or delete them first",
+		MsgCategoryCycle:          "a category cannot be moved under itself or one of its subcategories",
+
+		MsgAdminRequired: "admin role required",
+
+		MsgProductImageUploaded:     "Image uploaded successfully",
+		MsgProductImageUploadFailed: "Failed to upload image",
+		MsgProductImagesUpdated:     "Images updated successfully",
+		MsgProductImagesUpdateFail:  "Failed to update images",
+		MsgProductImageDeleted:      "Image deleted successfully",
+		MsgProductImageDeleteFailed: "Failed to delete image",
+		MsgProductImageNotFound:     "Image not found",
+		MsgProductImageRequired:     "an image file is required in the image form field",
+		MsgProductImageLimit:        "product already has the maximum number of images",
+		MsgProductImageOrder:        "image_ids must list each of the product's images exactly once",
+		MsgImageTooLarge:            "image file is too large",
+		MsgImageUnsupportedType:     "image must be JPEG, PNG, GIF or WebP",
+		MsgImageInvalid:             "image file is corrupt or cannot be decoded",
+		MsgImageTooManyPixels:       "image dimensions are too large",
+
+		MsgVariantsWithoutOptions: "options and variants must be given together",
+		MsgDuplicateOption:        "option names and the values of each option must be unique",
+		MsgVariantOptions:         "each variant must have one of the allowed values for every option",
+		MsgDuplicateVariant:       "variants must have distinct option values",
+		MsgDuplicateSKU:           "variant SKUs must be unique",
+		MsgSKUTaken:               "SKU is already used by another product",
+		MsgUnknownVariant:         "variant does not exist",
+
+		MsgOrderPlaced:         "Order placed successfully",
+		MsgOrderPlaceFailed:    "Failed to place order",
+		MsgOrderNotFound:       "Order not found",
+		MsgOrderRetrieved:      "Order retrieved successfully",
+		MsgOrderRetrieveFailed: "Failed to retrieve order",
+		MsgOrdersRetrieved:     "Orders retrieved successfully",
+		MsgOrdersRetrieveFail:  "Failed to retrieve orders",
+		MsgVariantRequired:     "variant_id is required for products with variants",
+		MsgInsufficientStock:   "not enough stock to fulfil the order",
+		MsgMixedCurrencies:     "all items of an order must be priced in the same currency; pass currency to convert them",
+		MsgOrderTotalRange:     "the order total is out of range",
+
+		MsgExchangeRatesRetrieved:    "Exchange rates retrieved successfully",
+		MsgExchangeRatesRetrieveFail: "Failed to retrieve exchange rates",
+		MsgExchangeRateSet:           "Exchange rate saved successfully",
+		MsgExchangeRateSetFailed:     "Failed to save exchange rate",
+		MsgExchangeRateDeleted:       "Exchange rate deleted successfully",
+		MsgExchangeRateDeleteFailed:  "Failed to delete exchange rate",
+		MsgExchangeRateNotFound:      "Exchange rate not found",
+		MsgExchangeRateUnavailable:   "no exchange rate is available for the requested currency",
+		MsgInvalidCurrency:           "currency must be an ISO 4217 code such as USD",
+		MsgSameCurrency:              "the base and quote currencies must differ",
+		MsgConversionOutOfRange:      "the converted price is out of range",
+	},
+	LocaleID: {
+		MsgNotAuthenticated:       "Pengguna belum terautentikasi",
+		MsgAuthHeaderRequired:     "Header Authorization wajib diisi",
+		MsgInvalidAuthHeader:      "Format header Authorization tidak valid",
+		MsgInvalidToken:           "Token tidak valid",
+		MsgTokenExpired:           "token sudah kedaluwarsa",
+		MsgInvalidRequestBody:     "Body permintaan tidak valid",
+		MsgUnsupportedContentType: "Tipe konten tidak didukung",
+		MsgValidationFailed:       "validasi gagal",
+		MsgValidationType:         "%s harus bertipe %s",
+		MsgInternalServerError:    "Terjadi kesalahan pada server",
+		MsgInvalidID:              "Format ID tidak valid",
+		MsgInvalidMergePatch:      "Dokumen merge patch tidak valid",
+		MsgRateLimited:            "Terlalu banyak permintaan, silakan coba lagi nanti",
+		MsgInvalidParameters:      "Parameter permintaan tidak valid",
+
+		MsgSchemaRequired:         "%[1]s wajib diisi",
+		MsgSchemaType:             "%[1]s harus bertipe %[2]s",
+		MsgSchemaEnum:             "%[1]s harus salah satu dari [%[2]s]",
+		MsgSchemaPattern:          "format %[1]s tidak valid",
+		MsgSchemaFormat:           "%[1]s harus berupa %[2]s yang valid",
+		MsgSchemaMinimum:          "%[1]s harus %[2]s atau lebih besar",
+		MsgSchemaMaximum:          "%[1]s harus %[2]s atau lebih kecil",
+		MsgSchemaExclusiveMinimum: "%[1]s harus lebih besar dari %[2]s",
+		MsgSchemaExclusiveMaximum: "%[1]s harus lebih kecil dari %[2]s",
+		MsgSchemaMinLength:        "panjang %[1]s minimal %[2]s karakter",
+		MsgSchemaMaxLength:        "panjang %[1]s maksimal %[2]s karakter",
+		MsgSchemaMinItems:         "%[1]s harus berisi minimal %[2]s item",
+		MsgSchemaMaxItems:         "%[1]s harus berisi maksimal %[2]s item",
+		MsgSchemaOneOf:            "%[1]s tidak cocok dengan nilai yang diizinkan",
+
+		MsgUserCreated:           "Pengguna berhasil dibuat",
+		MsgUserCreateFailed:      "Gagal membuat pengguna",
+		MsgLoginSuccessful:       "Login berhasil",
+		MsgLoginFailed:           "Login gagal",
+		MsgUserNotFound:          "Pengguna tidak ditemukan",
+		MsgProfileRetrieved:      "Profil pengguna berhasil diambil",
+		MsgUserUpdated:           "Pengguna berhasil diperbarui",
+		MsgUserUpdateFailed:      "Gagal memperbarui pengguna",
+		MsgUsersRetrieved:        "Daftar pengguna berhasil diambil",
+		MsgUsersRetrieveFail:     "Gagal mengambil daftar pengguna",
+		MsgUserDeleted:           "Pengguna berhasil dihapus",
+		MsgUserDeleteFailed:      "Gagal menghapus pengguna",
+		MsgProfileRetrieveFailed: "Gagal mengambil profil pengguna",
+		MsgEmailExists:           "pengguna dengan email ini sudah terdaftar",
+		MsgEmailTaken:            "email sudah digunakan",
+		MsgInvalidCredentials:    "email atau kata sandi salah",
+
+		MsgProductCreated:         "Produk berhasil dibuat",
+		MsgProductCreateFailed:    "Gagal membuat produk",
+		MsgProductNotFound:        "Produk tidak ditemukan",
+		MsgProductRetrieved:       "Produk berhasil diambil",
+		MsgProductsRetrieved:      "Daftar produk berhasil diambil",
+		MsgProductsRetrieveFail:   "Gagal mengambil daftar produk",
+		MsgProductUpdated:         "Produk berhasil diperbarui",
+		MsgProductUpdateFailed:    "Gagal memperbarui produk",
+		MsgProductDeleted:         "Produk berhasil dihapus",
+		MsgProductDeleteFailed:    "Gagal menghapus produk",
+		MsgProductRetrieveFailed:  "Gagal mengambil produk",
+		MsgProductUpdateForbidden: "anda hanya dapat memperbarui produk milik anda sendiri",
+		MsgProductDeleteForbidden: "anda hanya dapat menghapus produk milik anda sendiri",
+		MsgProductModified:        "produk berubah saat sedang diperbarui; ambil ulang lalu coba lagi",
+		MsgUnknownCategory:        "satu atau lebih kategori tidak ditemukan",
+		MsgPricePrecision:         "harga tidak boleh memiliki desimal lebih banyak dari mata uangnya",
+		MsgPriceFilterCurrency:    "min_price dan max_price memerlukan currency",
+
+		MsgCategoryCreated:        "Kategori berhasil dibuat",
+		MsgCategoryCreateFailed:   "Gagal membuat kategori",
+		MsgCategoryNotFound:       "Kategori tidak ditemukan",
+		MsgCategoryRetrieved:      "Kategori berhasil diambil",
+		MsgCategoryRetrieveFailed: "Gagal mengambil kategori",
+		MsgCategoriesRetrieved:    "Daftar kategori berhasil diambil",
+		MsgCategoriesRetrieveFail: "Gagal mengambil daftar kategori",
+		MsgCategoryUpdated:        "Kategori berhasil diperbarui",
+		MsgCategoryUpdateFailed:   "Gagal memperbarui kategori",
+		MsgCategoryDeleted:        "Kategori berhasil dihapus",
+		MsgCategoryDeleteFailed:   "Gagal menghapus kategori",
+		MsgCategorySlugTaken:      "slug kategori sudah digunakan",
+		MsgCategoryHasChildren:    "kategori memiliki subkategori; pindahkan atau hapus terlebih dahulu",
+		MsgCategoryCycle:          "kategori tidak dapat dipindahkan ke bawah dirinya sendiri atau subkategorinya",
+
+		MsgAdminRequired: "memerlukan peran admin",
+
+		MsgProductImageUploaded:     "Gambar berhasil diunggah",
+		MsgProductImageUploadFailed: "Gagal mengunggah gambar",
+		MsgProductImagesUpdated:     "Gambar berhasil diperbarui",
+		MsgProductImagesUpdateFail:  "Gagal memperbarui gambar",
+		MsgProductImageDeleted:      "Gambar berhasil dihapus",
+		MsgProductImageDeleteFailed: "Gagal menghapus gambar",
+		MsgProductImageNotFound:     "Gambar tidak ditemukan",
+		MsgProductImageRequired:     "file gambar wajib dikirim pada field formulir image",
+		MsgProductImageLimit:        "produk sudah memiliki jumlah gambar maksimum",
+		MsgProductImageOrder:        "image_ids harus memuat setiap gambar produk tepat satu kali",
+		MsgImageTooLarge:            "ukuran file gambar terlalu besar",
+		MsgImageUnsupportedType:     "gambar harus berformat JPEG, PNG, GIF atau WebP",
+		MsgImageInvalid:             "file gambar rusak atau tidak dapat dibaca",
+		MsgImageTooManyPixels:       "dimensi gambar terlalu besar",
+
+		MsgVariantsWithoutOptions: "opsi dan varian harus diberikan bersamaan",
+		MsgDuplicateOption:        "nama opsi dan nilai setiap opsi harus unik",
+		MsgVariantOptions:         "setiap varian harus memiliki salah satu nilai yang diizinkan untuk setiap opsi",
+		MsgDuplicateVariant:       "varian harus memiliki nilai opsi yang berbeda",
+		MsgDuplicateSKU:           "SKU varian harus unik",
+		MsgSKUTaken:               "SKU sudah digunakan oleh produk lain",
+		MsgUnknownVariant:         "varian tidak ditemukan",
+
+		MsgOrderPlaced:         "Pesanan berhasil dibuat",
+		MsgOrderPlaceFailed:    "Gagal membuat pesanan",
+		MsgOrderNotFound:       "Pesanan tidak ditemukan",
+		MsgOrderRetrieved:      "Pesanan berhasil diambil",
+		MsgOrderRetrieveFailed: "Gagal mengambil pesanan",
+		MsgOrdersRetrieved:     "Daftar pesanan berhasil diambil",
+		MsgOrdersRetrieveFail:  "Gagal mengambil daftar pesanan",
+		MsgVariantRequired:     "variant_id wajib diisi untuk produk yang memiliki varian",
+		MsgInsufficientStock:   "stok tidak mencukupi untuk pesanan ini",
+		MsgMixedCurrencies:     "semua item pesanan harus menggunakan mata uang yang sama; gunakan currency untuk mengonversinya",
+		MsgOrderTotalRange:     "total pesanan di luar jangkauan",
+
+		MsgExchangeRatesRetrieved:    "Daftar kurs berhasil diambil",
+		MsgExchangeRatesRetrieveFail: "Gagal mengambil daftar kurs",
+		MsgExchangeRateSet:           "Kurs berhasil disimpan",
+		MsgExchangeRateSetFailed:     "Gagal menyimpan kurs",
+		MsgExchangeRateDeleted:       "Kurs berhasil dihapus",
+		MsgExchangeRateDeleteFailed:  "Gagal menghapus kurs",
+		MsgExchangeRateNotFound:      "Kurs tidak ditemukan",
+		MsgExchangeRateUnavailable:   "tidak ada kurs untuk mata uang yang diminta",
+		MsgInvalidCurrency:           "currency harus berupa kode ISO 4217 seperti USD",
+		MsgSameCurrency:              "mata uang dasar dan kuotasi harus berbeda",
+		MsgConversionOutOfRange:      "harga hasil konversi di luar jangkauan",
+	},
+}
@@ -0,0 +1,132 @@
+// Package images checks uploaded images and generates their thumbnails
+package images
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Errors returned by Process
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooManyPixels   = errors.New("image dimensions too large")
+	ErrInvalid         = errors.New("invalid image")
+)
+
+// Config limits uploads and sizes thumbnails
+type Config struct {
+	// MaxBytes is the largest accepted file
+	MaxBytes int64
+	// MaxPerProduct is the number of images a product can have
+	MaxPerProduct int
+	// MaxPixels bounds width × height, so small files cannot decode into
+	// huge bitmaps
+	MaxPixels int
+	// ThumbnailSize is the longest side of a thumbnail, in pixels
+	ThumbnailSize int
+}
+
+// extensions maps the accepted content types, as sniffed by
+// http.DetectContentType, to file extensions
+var extensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// Image is a checked upload with its thumbnail
+type Image struct {
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+
+	Thumbnail            []byte
+	ThumbnailContentType string
+	ThumbnailExtension   string
+}
+
+// Process sniffs the content type of data, ignoring what the client
+// claimed, checks its dimensions and renders a thumbnail. Thumbnails of
+// JPEG images are JPEG; the other formats may be transparent and get PNG
+// thumbnails.
+func Process(data []byte, cfg Config) (*Image, error) {
+	contentType := http.DetectContentType(data)
+	extension, ok := extensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrInvalid
+	}
+	if cfg.MaxPixels > 0 && config.Width*config.Height > cfg.MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooManyPixels, config.Width, config.Height)
+	}
+
+	var src image.Image
+	if contentType == "image/gif" {
+		// Only the first frame of an animation is used for the thumbnail
+		src, err = gif.Decode(bytes.NewReader(data))
+	} else {
+		src, _, err = image.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	img := &Image{
+		ContentType: contentType,
+		Extension:   extension,
+		Width:       config.Width,
+		Height:      config.Height,
+	}
+
+	thumbnail := resize(src, cfg.ThumbnailSize)
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 85})
+		img.ThumbnailContentType, img.ThumbnailExtension = "image/jpeg", "jpg"
+	} else {
+		err = png.Encode(&buf, thumbnail)
+		img.ThumbnailContentType, img.ThumbnailExtension = "image/png", "png"
+	}
+	if err != nil {
+		return nil, err
+	}
+	img.Thumbnail = buf.Bytes()
+
+	return img, nil
+}
+
+// resize scales src to fit in a size × size square, keeping its aspect
+// ratio; smaller images are not enlarged
+func resize(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if size <= 0 || (width <= size && height <= size) {
+		width, height = max(width, 1), max(height, 1)
+	} else if width >= height {
+		width, height = size, max(height*size/width, 1)
+	} else {
+		width, height = max(width*size/height, 1), size
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	return dst
+}
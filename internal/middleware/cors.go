@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"rest-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+type originPattern struct {
+	prefix string
+	suffix string
+}
+
+func (p originPattern) matches(origin string) bool {
+	return len(origin) > len(p.prefix)+len(p.suffix) &&
+		strings.HasPrefix(origin, p.prefix) &&
+		strings.HasSuffix(origin, p.suffix)
+}
+
+// CORSMiddleware answers preflight requests and sets the CORS response
+// headers for allowed origins. Requests from other origins get no CORS
+// headers, so browsers block them.
+func CORSMiddleware(cfg config.CORSConfig) gin.HandlerFunc {
+	anyOrigin := false
+	exact := make(map[string]bool)
+	var patterns []originPattern
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(origin)
+		switch {
+		case origin == "*":
+			anyOrigin = true
+		case strings.Contains(origin, "://*."):
+			i := strings.Index(origin, "*")
+			patterns = append(patterns, originPattern{prefix: origin[:i], suffix: origin[i+1:]})
+		default:
+			exact[origin] = true
+		}
+	}
+
+	// Browsers reject credentialed responses with a wildcard origin, and
+	// reflecting every origin would expose cookies to any site
+	if anyOrigin && cfg.AllowCredentials {
+		slog.Warn("CORS credentials cannot be combined with a wildcard origin, disabling credentials")
+		cfg.AllowCredentials = false
+	}
+
+	allowedMethods := make(map[string]bool)
+	for _, method := range cfg.AllowedMethods {
+		allowedMethods[strings.ToUpper(method)] = true
+	}
+	allowedHeaders := make(map[string]bool)
+	for _, header := range cfg.AllowedHeaders {
+		allowedHeaders[http.CanonicalHeaderKey(header)] = true
+	}
+
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	exposed := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	originAllowed := func(origin string) bool {
+		if anyOrigin {
+			return true
+		}
+		origin = strings.ToLower(origin)
+		if exact[origin] {
+			return true
+		}
+		for _, pattern := range patterns {
+			if pattern.matches(origin) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		// The response depends on the origin unless every origin gets "*"
+		if !anyOrigin {
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		if preflight {
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+
+			if !originAllowed(origin) || !allowedMethods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))] {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			for _, header := range strings.Split(c.GetHeader("Access-Control-Request-Headers"), ",") {
+				if header = strings.TrimSpace(header); header != "" && !allowedHeaders[http.CanonicalHeaderKey(header)] {
+					c.AbortWithStatus(http.StatusForbidden)
+					return
+				}
+			}
+
+			setAllowOrigin(c, origin, anyOrigin, cfg.AllowCredentials)
+			c.Header("Access-Control-Allow-Methods", methods)
+			if headers != "" {
+				c.Header("Access-Control-Allow-Headers", headers)
+			}
+			if cfg.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if originAllowed(origin) {
+			setAllowOrigin(c, origin, anyOrigin, cfg.AllowCredentials)
+			if exposed != "" {
+				c.Header("Access-Control-Expose-Headers", exposed)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func setAllowOrigin(c *gin.Context, origin string, anyOrigin, credentials bool) {
+	if anyOrigin {
+		c.Header("Access-Control-Allow-Origin", "*")
+		return
+	}
+	c.Header("Access-Control-Allow-Origin", origin)
+	if credentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+}
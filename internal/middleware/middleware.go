@@ -1,33 +1,30 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
 	"strings"
 
-	"rest-api/internal/models"
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/responder"
 	"rest-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, models.APIResponse{
-				Success: false,
-				Message: "Authorization header is required",
-			})
+			responder.Error(c, i18n.MsgAuthHeaderRequired, apperrors.ErrUnauthorized)
 			c.Abort()
 			return
 		}
 
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, models.APIResponse{
-				Success: false,
-				Message: "Invalid authorization header format",
-			})
+			responder.Error(c, i18n.MsgInvalidAuthHeader, apperrors.ErrUnauthorized)
 			c.Abort()
 			return
 		}
@@ -35,33 +32,48 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		token := tokenParts[1]
 		claims, err := utils.ValidateToken(token, jwtSecret)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, models.APIResponse{
-				Success: false,
-				Message: "Invalid token",
-				Error:   err.Error(),
-			})
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				responder.Error(c, i18n.MsgInvalidToken, apperrors.Unauthorized(i18n.MsgTokenExpired))
+			} else {
+				responder.Error(c, i18n.MsgInvalidToken, apperrors.ErrUnauthorized)
+			}
 			c.Abort()
 			return
 		}
 
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
+		c.Set("user_role", claims.Role)
+
+		// A saved user preference takes precedence over Accept-Language
+		if i18n.IsSupported(claims.Locale) {
+			c.Set("locale", claims.Locale)
+			c.Header("Content-Language", claims.Locale)
+		}
+
 		c.Next()
 	}
 }
 
-func CORSMiddleware() gin.HandlerFunc {
+// RequireRole rejects authenticated requests whose token does not carry
+// role. It must run after AuthMiddleware.
+func RequireRole(role string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if c.GetString("user_role") != role {
+			responder.Error(c, i18n.MsgAdminRequired, apperrors.Forbidden(i18n.MsgAdminRequired))
+			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
 
+// LocaleMiddleware negotiates the response locale from the Accept-Language header
+func LocaleMiddleware(defaultLocale string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.Negotiate(c.GetHeader("Accept-Language"), defaultLocale)
+		c.Set("locale", locale)
+		c.Header("Content-Language", locale)
 		c.Next()
 	}
 }
@@ -70,12 +82,8 @@ func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
-		if len(c.Errors) > 0 {
-			c.JSON(http.StatusInternalServerError, models.APIResponse{
-				Success: false,
-				Message: "Internal server error",
-				Error:   c.Errors.Last().Error(),
-			})
+		if len(c.Errors) > 0 && !c.Writer.Written() {
+			responder.Error(c, i18n.MsgInternalServerError, c.Errors.Last().Err)
 		}
 	}
 }
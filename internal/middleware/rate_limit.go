@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"rest-api/internal/i18n"
+	"rest-api/internal/logger"
+	"rest-api/internal/models"
+	"rest-api/internal/ratelimit"
+	"rest-api/internal/responder"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKeyFunc identifies the client a request is counted against
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByIP counts requests per client IP
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByUser counts requests per authenticated user, falling back to
+// the client IP. It must run after AuthMiddleware.
+func RateLimitByUser(c *gin.Context) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitMiddleware enforces limit per client using a token bucket. Name
+// scopes the buckets so that route groups are limited independently.
+func RateLimitMiddleware(store ratelimit.Store, name string, limit ratelimit.Limit, key RateLimitKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		result, err := store.Take(c.Request.Context(), name+":"+key(c), limit)
+		if err != nil {
+			// Fail open so an unavailable store does not take the API down
+			logger.FromContext(c.Request.Context()).Error("rate limit store error", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			responder.Fail(c, http.StatusTooManyRequests, models.ErrCodeRateLimited, i18n.MsgRateLimited)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
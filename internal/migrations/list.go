@@ -0,0 +1,232 @@
+package migrations
+
+import (
+	"context"
+
+	"rest-api/internal/money"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// all lists every migration; append new ones with the next version number
+var all = []Migration{
+	{
+		Version:     1,
+		Description: "create user and product indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("users").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetName("email_unique").SetUnique(true),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = db.Collection("products").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "created_at", Value: -1}},
+					Options: options.Index().SetName("created_at"),
+				},
+				{
+					Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+					Options: options.Index().SetName("user_id_created_at"),
+				},
+			})
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			if _, err := db.Collection("users").Indexes().DropOne(ctx, "email_unique"); err != nil {
+				return err
+			}
+			if _, err := db.Collection("products").Indexes().DropOne(ctx, "created_at"); err != nil {
+				return err
+			}
+			_, err := db.Collection("products").Indexes().DropOne(ctx, "user_id_created_at")
+			return err
+		},
+	},
+	{
+		Version:     2,
+		Description: "create category indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("categories").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "slug", Value: 1}},
+					Options: options.Index().SetName("slug_unique").SetUnique(true),
+				},
+				{
+					Keys:    bson.D{{Key: "path", Value: 1}},
+					Options: options.Index().SetName("path"),
+				},
+				{
+					Keys:    bson.D{{Key: "parent_id", Value: 1}},
+					Options: options.Index().SetName("parent_id"),
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = db.Collection("products").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "category_ids", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("category_ids_created_at"),
+			})
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			for _, name := range []string{"slug_unique", "path", "parent_id"} {
+				if _, err := db.Collection("categories").Indexes().DropOne(ctx, name); err != nil {
+					return err
+				}
+			}
+			_, err := db.Collection("products").Indexes().DropOne(ctx, "category_ids_created_at")
+			return err
+		},
+	},
+	{
+		Version:     3,
+		Description: "create product tag index",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("products").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "tags", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("tags_created_at"),
+			})
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("products").Indexes().DropOne(ctx, "tags_created_at")
+			return err
+		},
+	},
+	{
+		Version:     4,
+		Description: "create variant SKU and order indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// Partial, so products without variants do not all index a
+			// missing SKU
+			_, err := db.Collection("products").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys: bson.D{{Key: "variants.sku", Value: 1}},
+				Options: options.Index().SetName("variants_sku_unique").SetUnique(true).
+					SetPartialFilterExpression(bson.M{"variants.sku": bson.M{"$exists": true}}),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = db.Collection("orders").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("user_id_created_at"),
+			})
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			if _, err := db.Collection("products").Indexes().DropOne(ctx, "variants_sku_unique"); err != nil {
+				return err
+			}
+			_, err := db.Collection("orders").Indexes().DropOne(ctx, "user_id_created_at")
+			return err
+		},
+	},
+	{
+		Version:     5,
+		Description: "store prices as decimals with a currency",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("products").UpdateMany(ctx,
+				bson.M{"$or": bson.A{
+					bson.M{"price": bson.M{"$not": bson.M{"$type": "decimal"}}},
+					bson.M{"currency": bson.M{"$exists": false}},
+					bson.M{"variants.price": bson.M{"$type": "double"}},
+				}},
+				mongo.Pipeline{{{Key: "$set", Value: bson.M{
+					"price":    toDecimal("$price"),
+					"currency": bson.M{"$ifNull": bson.A{"$currency", money.DefaultCurrency}},
+					"variants": convertPrices("$variants", toDecimal),
+				}}}},
+			)
+			if err != nil {
+				return err
+			}
+
+			_, err = db.Collection("orders").UpdateMany(ctx,
+				bson.M{"$or": bson.A{
+					bson.M{"total_amount": bson.M{"$not": bson.M{"$type": "decimal"}}},
+					bson.M{"currency": bson.M{"$exists": false}},
+				}},
+				mongo.Pipeline{{{Key: "$set", Value: bson.M{
+					"total_amount": toDecimal("$total_amount"),
+					"currency":     bson.M{"$ifNull": bson.A{"$currency", money.DefaultCurrency}},
+					"order_items":  convertPrices("$order_items", toDecimal),
+				}}}},
+			)
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("products").UpdateMany(ctx, bson.M{}, mongo.Pipeline{
+				{{Key: "$set", Value: bson.M{
+					"price":    toDouble("$price"),
+					"variants": convertPrices("$variants", toDouble),
+				}}},
+				{{Key: "$unset", Value: "currency"}},
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = db.Collection("orders").UpdateMany(ctx, bson.M{}, mongo.Pipeline{
+				{{Key: "$set", Value: bson.M{
+					"total_amount": toDouble("$total_amount"),
+					"order_items":  convertPrices("$order_items", toDouble),
+				}}},
+				{{Key: "$unset", Value: "currency"}},
+			})
+			return err
+		},
+	},
+	{
+		Version:     6,
+		Description: "create exchange rate indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("exchange_rates").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "base", Value: 1}, {Key: "quote", Value: 1}},
+				Options: options.Index().SetName("base_quote_unique").SetUnique(true),
+			})
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("exchange_rates").Indexes().DropOne(ctx, "base_quote_unique")
+			return err
+		},
+	},
+}
+
+// toDecimal converts a number to a Decimal128 rounded to the places of a
+// money.Amount, dropping the binary noise of doubles
+func toDecimal(expr interface{}) bson.M {
+	return bson.M{"$round": bson.A{bson.M{"$toDecimal": expr}, money.Scale}}
+}
+
+func toDouble(expr interface{}) bson.M {
+	return bson.M{"$toDouble": expr}
+}
+
+// convertPrices converts the price of every element of an array field that
+// has one, leaving missing arrays missing
+func convertPrices(array string, convert func(interface{}) bson.M) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$isArray": array},
+		bson.M{"$map": bson.M{
+			"input": array,
+			"in": bson.M{"$mergeObjects": bson.A{
+				"$$this",
+				bson.M{"$cond": bson.A{
+					bson.M{"$eq": bson.A{bson.M{"$type": "$$this.price"}, "missing"}},
+					bson.M{},
+					bson.M{"price": convert("$$this.price")},
+				}},
+			}},
+		}},
+		"$$REMOVE",
+	}}
+}
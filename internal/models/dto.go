@@ -1,10 +1,13 @@
 package models
 
+import "rest-api/internal/money"
+
 // Request DTOs
 type CreateUserRequest struct {
 	Name     string `json:"name" validate:"required,min=2,max=100"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
+	Locale   string `json:"locale" validate:"omitempty,oneof=en id"`
 }
 
 type LoginRequest struct {
@@ -13,31 +16,117 @@ type LoginRequest struct {
 }
 
 type UpdateUserRequest struct {
-	Name  string `json:"name" validate:"omitempty,min=2,max=100"`
-	Email string `json:"email" validate:"omitempty,email"`
+	Name   string `json:"name" validate:"omitempty,min=2,max=100"`
+	Email  string `json:"email" validate:"omitempty,email"`
+	Locale string `json:"locale" validate:"omitempty,oneof=en id"`
+}
+
+// PatchUserRequest is the result of applying a JSON merge patch to the
+// current profile; a nil field means the patch set it to null
+type PatchUserRequest struct {
+	Name   *string `json:"name" validate:"required,min=2,max=100"`
+	Email  *string `json:"email" validate:"required,email"`
+	Locale *string `json:"locale" validate:"omitempty,oneof=en id"`
 }
 
+// CreateProductRequest creates a product; with variants, stock is the sum
+// of the variant stocks and may be omitted
 type CreateProductRequest struct {
-	Name        string  `json:"name" validate:"required,min=2,max=100"`
-	Description string  `json:"description" validate:"max=500"`
-	Price       float64 `json:"price" validate:"required,gt=0"`
-	Stock       int     `json:"stock" validate:"required,gte=0"`
+	Name        string       `json:"name" validate:"required,min=2,max=100"`
+	Description string       `json:"description" validate:"max=500"`
+	Price       money.Amount `json:"price" validate:"required,gt=0"`
+	// Currency is an ISO 4217 code, USD when omitted
+	Currency    string                  `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	Stock       int                     `json:"stock" validate:"required_without=Variants,gte=0"`
+	CategoryIDs []string                `json:"category_ids,omitempty" validate:"omitempty,max=10,dive,mongodb"`
+	Tags        []string                `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=30"`
+	Options     []ProductOptionRequest  `json:"options,omitempty" validate:"omitempty,max=3,dive"`
+	Variants    []ProductVariantRequest `json:"variants,omitempty" validate:"omitempty,max=100,dive"`
 }
 
+// UpdateProductRequest changes the non-empty fields of a product; an empty
+// category_ids, tags, options or variants list removes them all
 type UpdateProductRequest struct {
+	Name        string                  `json:"name" validate:"omitempty,min=2,max=100"`
+	Description string                  `json:"description" validate:"omitempty,max=500"`
+	Price       money.Amount            `json:"price" validate:"omitempty,gt=0"`
+	Currency    string                  `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	Stock       *int                    `json:"stock" validate:"omitempty,gte=0"`
+	CategoryIDs []string                `json:"category_ids,omitempty" validate:"omitempty,max=10,dive,mongodb"`
+	Tags        []string                `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=30"`
+	Options     []ProductOptionRequest  `json:"options,omitempty" validate:"omitempty,max=3,dive"`
+	Variants    []ProductVariantRequest `json:"variants,omitempty" validate:"omitempty,max=100,dive"`
+}
+
+// PatchProductRequest is the result of applying a JSON merge patch to the
+// current product; a nil field means the patch set it to null
+type PatchProductRequest struct {
+	Name        *string                  `json:"name" validate:"required,min=2,max=100"`
+	Description *string                  `json:"description" validate:"omitempty,max=500"`
+	Price       *money.Amount            `json:"price" validate:"required,gt=0"`
+	Currency    *string                  `json:"currency" validate:"required,iso4217"`
+	Stock       *int                     `json:"stock" validate:"required,gte=0"`
+	CategoryIDs *[]string                `json:"category_ids" validate:"omitempty,max=10,dive,mongodb"`
+	Tags        *[]string                `json:"tags" validate:"omitempty,max=20,dive,min=1,max=30"`
+	Options     *[]ProductOptionRequest  `json:"options" validate:"omitempty,max=3,dive"`
+	Variants    *[]ProductVariantRequest `json:"variants" validate:"omitempty,max=100,dive"`
+}
+
+type ProductOptionRequest struct {
+	Name   string   `json:"name" validate:"required,min=1,max=50"`
+	Values []string `json:"values" validate:"required,min=1,max=20,dive,min=1,max=50"`
+}
+
+// ProductVariantRequest describes a variant. On update, a variant keeps its
+// ID when the request gives it, or when its SKU is unchanged.
+type ProductVariantRequest struct {
+	ID      string            `json:"id,omitempty" validate:"omitempty,mongodb"`
+	SKU     string            `json:"sku" validate:"required,min=1,max=64"`
+	Options map[string]string `json:"options" validate:"required"`
+	Price   *money.Amount     `json:"price,omitempty" validate:"omitempty,gt=0"`
+	Stock   int               `json:"stock" validate:"gte=0"`
+}
+
+// ReorderProductImagesRequest lists every image of a product in its new
+// order; the first becomes the primary image
+type ReorderProductImagesRequest struct {
+	ImageIDs []string `json:"image_ids" validate:"required,min=1,dive,mongodb"`
+}
+
+type CreateCategoryRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+	// Slug defaults to one derived from the name
+	Slug        string `json:"slug,omitempty" validate:"omitempty,min=2,max=100,slug"`
+	Description string `json:"description" validate:"max=500"`
+	ParentID    string `json:"parent_id,omitempty" validate:"omitempty,mongodb"`
+}
+
+// UpdateCategoryRequest changes the non-empty fields of a category. A
+// parent_id moves the category with its subtree; an empty one makes it a
+// root category.
+type UpdateCategoryRequest struct {
 	Name        string  `json:"name" validate:"omitempty,min=2,max=100"`
+	Slug        string  `json:"slug" validate:"omitempty,min=2,max=100,slug"`
 	Description string  `json:"description" validate:"omitempty,max=500"`
-	Price       float64 `json:"price" validate:"omitempty,gt=0"`
-	Stock       int     `json:"stock" validate:"omitempty,gte=0"`
+	ParentID    *string `json:"parent_id,omitempty"`
 }
 
 type CreateOrderRequest struct {
 	Items []OrderItemRequest `json:"items" validate:"required,min=1,dive"`
 }
 
+// OrderItemRequest orders a quantity of a product, naming the variant when
+// the product has variants
 type OrderItemRequest struct {
-	ProductID string `json:"product_id" validate:"required"`
-	Quantity  int    `json:"quantity" validate:"required,gt=0"`
+	ProductID string `json:"product_id" validate:"required,mongodb"`
+	VariantID string `json:"variant_id,omitempty" validate:"omitempty,mongodb"`
+	Quantity  int    `json:"quantity" validate:"required,gt=0,max=1000"`
+}
+
+// SetExchangeRateRequest sets the price of one unit of the base currency in
+// the quote currency
+type SetExchangeRateRequest struct {
+	Rate money.Rate `json:"rate" validate:"required,gt=0"`
 }
 
 type UpdateOrderStatusRequest struct {
@@ -49,6 +138,8 @@ type UserResponse struct {
 	ID        string `json:"id"`
 	Name      string `json:"name"`
 	Email     string `json:"email"`
+	Locale    string `json:"locale,omitempty"`
+	Role      string `json:"role,omitempty"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -58,42 +149,170 @@ type LoginResponse struct {
 	User  UserResponse `json:"user"`
 }
 
+// ProductResponse is a product; BasePrice is set when the prices were
+// converted from its currency into a requested one
 type ProductResponse struct {
-	ID          string       `json:"id"`
-	Name        string       `json:"name"`
-	Description string       `json:"description"`
-	Price       float64      `json:"price"`
-	Stock       int          `json:"stock"`
-	User        UserResponse `json:"user"`
-	CreatedAt   string       `json:"created_at"`
-	UpdatedAt   string       `json:"updated_at"`
+	ID          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Price       money.Money              `json:"price"`
+	BasePrice   *money.Money             `json:"base_price,omitempty"`
+	Stock       int                      `json:"stock"`
+	User        UserResponse             `json:"user"`
+	Categories  []ProductCategory        `json:"categories"`
+	Tags        []string                 `json:"tags"`
+	Images      []ProductImageResponse   `json:"images"`
+	Options     []ProductOption          `json:"options"`
+	Variants    []ProductVariantResponse `json:"variants"`
+	CreatedAt   string                   `json:"created_at"`
+	UpdatedAt   string                   `json:"updated_at"`
+}
+
+// ProductVariantResponse is a variant with its effective price. BasePrice
+// is set when the price was converted from the product's currency.
+type ProductVariantResponse struct {
+	ID        string            `json:"id"`
+	SKU       string            `json:"sku"`
+	Options   map[string]string `json:"options"`
+	Price     money.Money       `json:"price"`
+	BasePrice *money.Money      `json:"base_price,omitempty"`
+	Stock     int               `json:"stock"`
+}
+
+type ProductImageResponse struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Primary      bool   `json:"primary"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// ProductCategory is a category a product is assigned to, with the trail
+// of categories from the root down to it
+type ProductCategory struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Slug        string            `json:"slug"`
+	Breadcrumbs []CategorySummary `json:"breadcrumbs"`
+}
+
+type CategorySummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type CategoryResponse struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Slug        string            `json:"slug"`
+	Description string            `json:"description"`
+	ParentID    string            `json:"parent_id,omitempty"`
+	Depth       int               `json:"depth"`
+	Breadcrumbs []CategorySummary `json:"breadcrumbs"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
 }
 
+// OrderResponse is an order; ExchangeRates lists the rates its items were
+// converted at when it was placed in another currency than the products
 type OrderResponse struct {
-	ID          string              `json:"id"`
-	User        UserResponse        `json:"user"`
-	TotalAmount float64             `json:"total_amount"`
-	Status      string              `json:"status"`
-	OrderItems  []OrderItemResponse `json:"order_items"`
-	CreatedAt   string              `json:"created_at"`
-	UpdatedAt   string              `json:"updated_at"`
+	ID            string                 `json:"id"`
+	UserID        string                 `json:"user_id"`
+	TotalAmount   money.Money            `json:"total_amount"`
+	Status        string                 `json:"status"`
+	OrderItems    []OrderItemResponse    `json:"order_items"`
+	ExchangeRates []ExchangeRateResponse `json:"exchange_rates,omitempty"`
+	CreatedAt     string                 `json:"created_at"`
+	UpdatedAt     string                 `json:"updated_at"`
 }
 
 type OrderItemResponse struct {
-	ID       string          `json:"id"`
-	Product  ProductResponse `json:"product"`
-	Quantity int             `json:"quantity"`
-	Price    float64         `json:"price"`
+	ID        string            `json:"id"`
+	ProductID string            `json:"product_id"`
+	VariantID string            `json:"variant_id,omitempty"`
+	Name      string            `json:"name"`
+	SKU       string            `json:"sku,omitempty"`
+	Options   map[string]string `json:"options,omitempty"`
+	Quantity  int               `json:"quantity"`
+	Price     money.Money       `json:"price"`
+	Subtotal  money.Money       `json:"subtotal"`
+	// BasePrice is the product price Price was converted from
+	BasePrice *money.Money `json:"base_price,omitempty"`
+}
+
+// ExchangeRateResponse is the price of one unit of Base in Quote
+type ExchangeRateResponse struct {
+	Base      string     `json:"base"`
+	Quote     string     `json:"quote"`
+	Rate      money.Rate `json:"rate"`
+	UpdatedAt string     `json:"updated_at"`
+}
+
+type HealthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+	Build  interface{}            `json:"build"`
+}
+
+type HealthCheck struct {
+	Status    string      `json:"status"`
+	LatencyMS float64     `json:"latency_ms"`
+	Error     string      `json:"error,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
 }
 
 // Generic responses
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success   bool         `json:"success"`
+	Message   string       `json:"message"`
+	Data      interface{}  `json:"data,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Code      string       `json:"code,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// FieldError describes a single invalid field in a request body
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ProblemDetails is an RFC 7807 error document, sent instead of APIResponse
+// when the client accepts application/problem+json
+type ProblemDetails struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
 }
 
+// Error codes returned in APIResponse.Code
+const (
+	ErrCodeBadRequest           = "BAD_REQUEST"
+	ErrCodeInvalidRequestBody   = "INVALID_REQUEST_BODY"
+	ErrCodeValidationFailed     = "VALIDATION_FAILED"
+	ErrCodeUnauthorized         = "UNAUTHORIZED"
+	ErrCodeForbidden            = "FORBIDDEN"
+	ErrCodeNotFound             = "NOT_FOUND"
+	ErrCodeConflict             = "CONFLICT"
+	ErrCodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	ErrCodePayloadTooLarge      = "PAYLOAD_TOO_LARGE"
+	ErrCodeRateLimited          = "RATE_LIMITED"
+	ErrCodeInternal             = "INTERNAL_ERROR"
+)
+
 type PaginatedResponse struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message"`
@@ -102,3 +321,41 @@ type PaginatedResponse struct {
 	Limit   int         `json:"limit"`
 	Total   int64       `json:"total"`
 }
+
+// ProductListResponse is a page of products with facet counts over every
+// product matching the filter
+type ProductListResponse struct {
+	PaginatedResponse
+	Facets ProductFacets `json:"facets"`
+}
+
+type ProductFacets struct {
+	Tags         []TagFacet        `json:"tags"`
+	Categories   []CategoryFacet   `json:"categories"`
+	PriceRanges  []PriceRangeFacet `json:"price_ranges"`
+	Availability AvailabilityFacet `json:"availability"`
+}
+
+type TagFacet struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+type CategoryFacet struct {
+	CategorySummary
+	Count int64 `json:"count"`
+}
+
+// PriceRangeFacet counts products priced in Currency from Min up to but
+// excluding Max; the last range of each currency has no Max
+type PriceRangeFacet struct {
+	Currency string        `json:"currency"`
+	Min      money.Amount  `json:"min"`
+	Max      *money.Amount `json:"max"`
+	Count    int64         `json:"count"`
+}
+
+type AvailabilityFacet struct {
+	InStock    int64 `json:"in_stock"`
+	OutOfStock int64 `json:"out_of_stock"`
+}
@@ -3,14 +3,24 @@ package models
 import (
 	"time"
 
+	"rest-api/internal/money"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Name      string             `json:"name" bson:"name" validate:"required,min=2,max=100"`
 	Email     string             `json:"email" bson:"email" validate:"required,email"`
 	Password  string             `json:"-" bson:"password" validate:"required,min=6"`
+	Locale    string             `json:"locale,omitempty" bson:"locale,omitempty"`
+	Role      string             `json:"role,omitempty" bson:"role,omitempty"`
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
 }
@@ -19,31 +29,118 @@ type Product struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Name        string             `json:"name" bson:"name" validate:"required,min=2,max=100"`
 	Description string             `json:"description" bson:"description" validate:"max=500"`
-	Price       float64            `json:"price" bson:"price" validate:"required,gt=0"`
-	Stock       int                `json:"stock" bson:"stock" validate:"required,gte=0"`
-	UserID      primitive.ObjectID `json:"user_id" bson:"user_id"`
-	User        User               `json:"user,omitempty" bson:"-"`
-	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+	Price       money.Amount       `json:"price" bson:"price" validate:"required,gt=0"`
+	// Currency is the ISO 4217 code of the price and the variant prices
+	Currency    string               `json:"currency" bson:"currency" validate:"required,iso4217"`
+	Stock       int                  `json:"stock" bson:"stock" validate:"required,gte=0"`
+	UserID      primitive.ObjectID   `json:"user_id" bson:"user_id"`
+	User        User                 `json:"user,omitempty" bson:"-"`
+	CategoryIDs []primitive.ObjectID `json:"category_ids,omitempty" bson:"category_ids,omitempty"`
+	Tags        []string             `json:"tags,omitempty" bson:"tags,omitempty"`
+	Images      []ProductImage       `json:"images,omitempty" bson:"images,omitempty"`
+	Options     []ProductOption      `json:"options,omitempty" bson:"options,omitempty"`
+	Variants    []ProductVariant     `json:"variants,omitempty" bson:"variants,omitempty"`
+	CreatedAt   time.Time            `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at" bson:"updated_at"`
+}
+
+// ProductOption is a dimension a product's variants differ in, e.g. size
+// with the values S, M and L
+type ProductOption struct {
+	Name   string   `json:"name" bson:"name"`
+	Values []string `json:"values" bson:"values"`
+}
+
+// ProductVariant is a purchasable combination of option values with its
+// own SKU and stock. A product with variants is sold only through them, and
+// its Stock is the sum of theirs.
+type ProductVariant struct {
+	ID  primitive.ObjectID `json:"id" bson:"_id"`
+	SKU string             `json:"sku" bson:"sku"`
+	// Options maps every option name of the product to one of its values
+	Options map[string]string `json:"options" bson:"options"`
+	// Price overrides the product price when set
+	Price *money.Amount `json:"price,omitempty" bson:"price,omitempty"`
+	Stock int           `json:"stock" bson:"stock"`
 }
 
+// ProductImage is an uploaded image, stored under Key with its thumbnail
+// under ThumbnailKey. A product's first image is its primary one.
+type ProductImage struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	Key          string             `json:"key" bson:"key"`
+	ThumbnailKey string             `json:"thumbnail_key" bson:"thumbnail_key"`
+	ContentType  string             `json:"content_type" bson:"content_type"`
+	Size         int64              `json:"size" bson:"size"`
+	Width        int                `json:"width" bson:"width"`
+	Height       int                `json:"height" bson:"height"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// Category is a node in the product taxonomy. Path is the materialized
+// path of ancestor IDs ending with the category's own ID, e.g.
+// "/<root>/<parent>/<id>/", so descendants are found by prefix.
+type Category struct {
+	ID          primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Name        string              `json:"name" bson:"name"`
+	Slug        string              `json:"slug" bson:"slug"`
+	Description string              `json:"description" bson:"description"`
+	ParentID    *primitive.ObjectID `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Path        string              `json:"path" bson:"path"`
+	Depth       int                 `json:"depth" bson:"depth"`
+	CreatedAt   time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// Order statuses
+const (
+	OrderPending    = "pending"
+	OrderProcessing = "processing"
+	OrderCompleted  = "completed"
+	OrderCancelled  = "cancelled"
+)
+
 type Order struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	UserID      primitive.ObjectID `json:"user_id" bson:"user_id"`
 	User        User               `json:"user,omitempty" bson:"-"`
-	TotalAmount float64            `json:"total_amount" bson:"total_amount" validate:"required,gt=0"`
+	TotalAmount money.Amount       `json:"total_amount" bson:"total_amount" validate:"required,gt=0"`
+	Currency    string             `json:"currency" bson:"currency" validate:"required,iso4217"`
 	Status      string             `json:"status" bson:"status" validate:"oneof=pending processing completed cancelled"`
 	OrderItems  []OrderItem        `json:"order_items" bson:"order_items"`
-	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+	// ExchangeRates are copies of the rates the items were converted into
+	// Currency at, as they stood when the order was placed
+	ExchangeRates []ExchangeRate `json:"exchange_rates,omitempty" bson:"exchange_rates,omitempty"`
+	CreatedAt     time.Time      `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" bson:"updated_at"`
 }
 
+// OrderItem is one line of an order. The product name, SKU, options and
+// price are copied at order time so later product changes do not alter it.
 type OrderItem struct {
+	ID        primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	ProductID primitive.ObjectID  `json:"product_id" bson:"product_id"`
+	VariantID *primitive.ObjectID `json:"variant_id,omitempty" bson:"variant_id,omitempty"`
+	Name      string              `json:"name" bson:"name"`
+	SKU       string              `json:"sku,omitempty" bson:"sku,omitempty"`
+	Options   map[string]string   `json:"options,omitempty" bson:"options,omitempty"`
+	Quantity  int                 `json:"quantity" bson:"quantity" validate:"required,gt=0"`
+	Price     money.Amount        `json:"price" bson:"price" validate:"required,gt=0"`
+	// BasePrice is the product price Price was converted from, when the
+	// order is in another currency than the product
+	BasePrice *money.Money `json:"base_price,omitempty" bson:"base_price,omitempty"`
+	CreatedAt time.Time    `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" bson:"updated_at"`
+}
+
+// ExchangeRate is the price of one unit of the Base currency in the Quote
+// currency. Amounts are converted from Base to Quote by multiplying by Rate,
+// and from Quote to Base by dividing by it.
+type ExchangeRate struct {
 	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	ProductID primitive.ObjectID `json:"product_id" bson:"product_id"`
-	Product   Product            `json:"product,omitempty" bson:"-"`
-	Quantity  int                `json:"quantity" bson:"quantity" validate:"required,gt=0"`
-	Price     float64            `json:"price" bson:"price" validate:"required,gt=0"`
+	Base      string             `json:"base" bson:"base"`
+	Quote     string             `json:"quote" bson:"quote"`
+	Rate      money.Rate         `json:"rate" bson:"rate"`
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
 }
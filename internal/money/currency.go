@@ -0,0 +1,58 @@
+package money
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// minorDigits lists the currencies whose minor unit is not a hundredth,
+// per ISO 4217; every other currency has two decimal places
+var minorDigits = map[string]int{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// Decimals returns the number of decimal places of a currency's minor unit
+func Decimals(currency string) int {
+	if digits, ok := minorDigits[strings.ToUpper(currency)]; ok {
+		return digits
+	}
+	return 2
+}
+
+// FromMinor converts a whole number of a currency's minor units, such as
+// cents, to an Amount
+func FromMinor(minor int64, currency string) Amount {
+	amount := Amount(minor)
+	for i := Decimals(currency); i < Scale; i++ {
+		amount *= 10
+	}
+	return amount
+}
+
+// Money is an amount in a currency, identified by its ISO 4217 code
+type Money struct {
+	Amount   Amount `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// New returns amount in currency
+func New(amount Amount, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// String writes the amount with the currency's decimal places followed by
+// the currency, e.g. "19.99 USD"
+func (m Money) String() string {
+	return m.Amount.Format(Decimals(m.Currency)) + " " + m.Currency
+}
+
+// MarshalJSON writes the amount with the currency's decimal places
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Amount.Format(Decimals(m.Currency)), m.Currency})
+}
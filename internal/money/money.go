@@ -0,0 +1,307 @@
+// Package money holds exact monetary amounts. An Amount is a fixed-point
+// decimal stored in MongoDB as Decimal128 and written in JSON as a string,
+// so prices and totals never pick up binary floating-point errors.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// Scale is the number of decimal places an Amount holds
+const Scale = 4
+
+// Pattern matches the string form of an Amount
+const Pattern = `^-?[0-9]+(\.[0-9]{1,4})?$`
+
+// DefaultCurrency is used for prices stored before currencies were recorded
+const DefaultCurrency = "USD"
+
+// ErrInvalidAmount is returned for malformed amounts or ones with more than
+// Scale decimal places
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Amount is a decimal number with Scale decimal places, held as an integer
+// count of 1/10000ths
+type Amount int64
+
+// Parse reads a decimal such as "19.99" or "-5"
+func Parse(s string) (Amount, error) {
+	value, err := parseFixed(s, Scale)
+	return Amount(value), err
+}
+
+// parseFixed reads a decimal with at most scale decimal places as an
+// integer count of 10^-scale units
+func parseFixed(s string, scale int) (int64, error) {
+	text := s
+	negative := strings.HasPrefix(text, "-")
+	if negative {
+		text = text[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(text, ".")
+	if whole == "" || (hasFraction && fraction == "") || len(fraction) > scale || !digits(whole) || !digits(fraction) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	fractionUnits := int64(0)
+	if scale > 0 {
+		fractionUnits, _ = strconv.ParseInt((fraction + strings.Repeat("0", scale))[:scale], 10, 64)
+	}
+	unit := pow10(scale)
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (math.MaxInt64-fractionUnits)/unit {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	value := units*unit + fractionUnits
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// MustParse is Parse for constants, panicking on malformed amounts
+func MustParse(s string) Amount {
+	amount, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// pow10 returns 10^n
+func pow10(n int) int64 {
+	value := int64(1)
+	for range n {
+		value *= 10
+	}
+	return value
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FromFloat converts a float, rounding to Scale decimal places. It is meant
+// for legacy data only.
+func FromFloat(f float64) Amount {
+	amount, _ := Parse(strconv.FormatFloat(f, 'f', Scale, 64))
+	return amount
+}
+
+// Add returns a + b, failing when the sum does not fit in an Amount
+func (a Amount) Add(b Amount) (Amount, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, fmt.Errorf("%w: %s + %s is out of range", ErrInvalidAmount, a, b)
+	}
+	return sum, nil
+}
+
+// Mul returns a times a whole quantity, failing when the product does not
+// fit in an Amount
+func (a Amount) Mul(quantity int) (Amount, error) {
+	product := a * Amount(quantity)
+	if quantity != 0 && (product/Amount(quantity) != a || (quantity == -1 && a == math.MinInt64)) {
+		return 0, fmt.Errorf("%w: %s * %d is out of range", ErrInvalidAmount, a, quantity)
+	}
+	return product, nil
+}
+
+// Round rounds to the given number of decimal places, halves away from zero
+func (a Amount) Round(decimals int) Amount {
+	if decimals >= Scale {
+		return a
+	}
+	step := Amount(1)
+	for i := decimals; i < Scale; i++ {
+		step *= 10
+	}
+	remainder := a % step
+	rounded := a - remainder
+	if 2*remainder >= step {
+		rounded += step
+	} else if 2*remainder <= -step {
+		rounded -= step
+	}
+	return rounded
+}
+
+// Format writes the amount with exactly the given number of decimal places,
+// rounding when it has more
+func (a Amount) Format(decimals int) string {
+	decimals = min(max(decimals, 0), Scale)
+	a = a.Round(decimals)
+	return formatFixed(int64(a), Scale, decimals)
+}
+
+// formatFixed writes a count of 10^-scale units with the given number of
+// decimal places, which must not exceed scale; extra digits are cut off
+func formatFixed(value int64, scale, decimals int) string {
+	sign := ""
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	unit := pow10(scale)
+	text := sign + strconv.FormatInt(value/unit, 10)
+	if decimals > 0 {
+		text += "." + fmt.Sprintf("%0*d", scale, value%unit)[:decimals]
+	}
+	return text
+}
+
+// String writes the amount without trailing zero decimals
+func (a Amount) String() string {
+	text := a.Format(Scale)
+	text = strings.TrimRight(text, "0")
+	return strings.TrimSuffix(text, ".")
+}
+
+// Decimal128 converts the amount for storage
+func (a Amount) Decimal128() primitive.Decimal128 {
+	value, _ := primitive.ParseDecimal128(a.Format(Scale))
+	return value
+}
+
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Amount) UnmarshalText(text []byte) error {
+	amount, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON accepts a decimal string, or a plain JSON number, which is
+// read from its text so it is not rounded through a float. Like the
+// standard decoder, it leaves the amount unchanged for null.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	amount, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+func (a Amount) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bsontype.Decimal128, bsoncore.AppendDecimal128(nil, a.Decimal128()), nil
+}
+
+// UnmarshalBSONValue reads Decimal128 amounts, and the doubles and integers
+// of documents written before amounts were decimals
+func (a *Amount) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value, err := unmarshalFixed(t, data, Scale)
+	if err != nil {
+		return err
+	}
+	*a = Amount(value)
+	return nil
+}
+
+// unmarshalFixed reads a BSON decimal, double or integer as a count of
+// 10^-scale units
+func unmarshalFixed(t bsontype.Type, data []byte, scale int) (int64, error) {
+	value := bsoncore.Value{Type: t, Data: data}
+	switch t {
+	case bsontype.Decimal128:
+		decimal, ok := value.Decimal128OK()
+		if !ok {
+			return 0, ErrInvalidAmount
+		}
+		return parseDecimal128(decimal, scale)
+	case bsontype.Double:
+		return parseFixed(strconv.FormatFloat(value.Double(), 'f', scale, 64), scale)
+	case bsontype.Int32:
+		return scaleInt(int64(value.Int32()), scale)
+	case bsontype.Int64:
+		return scaleInt(value.Int64(), scale)
+	default:
+		return 0, fmt.Errorf("%w: cannot decode BSON %s", ErrInvalidAmount, t)
+	}
+}
+
+// scaleInt converts a whole number to a count of 10^-scale units, failing
+// when it does not fit
+func scaleInt(value int64, scale int) (int64, error) {
+	unit := pow10(scale)
+	if value > math.MaxInt64/unit || value < math.MinInt64/unit {
+		return 0, fmt.Errorf("%w: %d is out of range", ErrInvalidAmount, value)
+	}
+	return value * unit, nil
+}
+
+// parseDecimal128 converts a stored decimal, rounding digits beyond scale
+// half away from zero; only values converted from doubles have them
+func parseDecimal128(value primitive.Decimal128, scale int) (int64, error) {
+	coefficient, exponent, err := value.BigInt()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+	}
+
+	text := coefficient.String()
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(text, "-")
+	if exponent >= 0 {
+		text += strings.Repeat("0", exponent)
+		exponent = 0
+	}
+	// Pad so there is at least one whole digit
+	if places := -exponent; len(text) <= places {
+		text = strings.Repeat("0", places-len(text)+1) + text
+	}
+	whole, fraction := text[:len(text)+exponent], text[len(text)+exponent:]
+
+	roundUp := len(fraction) > scale && fraction[scale] >= '5'
+	if len(fraction) > scale {
+		fraction = fraction[:scale]
+	}
+	if fraction != "" {
+		whole += "." + fraction
+	}
+	parsed, err := parseFixed(whole, scale)
+	if err != nil {
+		return 0, err
+	}
+	if roundUp {
+		if parsed == math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %s is out of range", ErrInvalidAmount, value)
+		}
+		parsed++
+	}
+	if negative {
+		parsed = -parsed
+	}
+	return parsed, nil
+}
@@ -0,0 +1,110 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestUnmarshalJSONNull(t *testing.T) {
+	var v struct {
+		Price Amount `json:"price"`
+		Rate  Rate   `json:"rate"`
+	}
+	v.Price = MustParse("19.99")
+	v.Rate = Rate(92000000)
+
+	if err := json.Unmarshal([]byte(`{"price": null, "rate": null}`), &v); err != nil {
+		t.Fatalf("unmarshal null: %v", err)
+	}
+	if v.Price != MustParse("19.99") || v.Rate != Rate(92000000) {
+		t.Errorf("null changed the values to %s and %s", v.Price, v.Rate)
+	}
+
+	if err := json.Unmarshal([]byte(`{"price": "5.5", "rate": 1.25}`), &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v.Price != MustParse("5.5") || v.Rate.String() != "1.25" {
+		t.Errorf("decoded %s and %s, want 5.5 and 1.25", v.Price, v.Rate)
+	}
+}
+
+func TestAddOverflow(t *testing.T) {
+	if sum, err := MustParse("1.5").Add(MustParse("-2.25")); err != nil || sum != MustParse("-0.75") {
+		t.Errorf("1.5 + -2.25 = %s, %v; want -0.75", sum, err)
+	}
+	for _, tt := range []struct{ a, b Amount }{
+		{math.MaxInt64, 1},
+		{math.MinInt64, -1},
+	} {
+		if _, err := tt.a.Add(tt.b); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("%d + %d: err = %v, want ErrInvalidAmount", tt.a, tt.b, err)
+		}
+	}
+}
+
+func TestMulOverflow(t *testing.T) {
+	if product, err := MustParse("19.99").Mul(3); err != nil || product != MustParse("59.97") {
+		t.Errorf("19.99 * 3 = %s, %v; want 59.97", product, err)
+	}
+	for _, tt := range []struct {
+		a        Amount
+		quantity int
+	}{
+		{math.MaxInt64/2 + 1, 2},
+		{math.MinInt64, -1},
+		{MustParse("922337203685477"), 1000},
+	} {
+		if _, err := tt.a.Mul(tt.quantity); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("%d * %d: err = %v, want ErrInvalidAmount", tt.a, tt.quantity, err)
+		}
+	}
+}
+
+func TestParseOverflow(t *testing.T) {
+	if _, err := Parse("922337203685477.5807"); err != nil {
+		t.Errorf("largest amount: %v", err)
+	}
+	for _, s := range []string{"922337203685477.5808", "922337203685478", "99999999999999999999"} {
+		if _, err := Parse(s); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Parse(%q): err = %v, want ErrInvalidAmount", s, err)
+		}
+	}
+}
+
+func TestUnmarshalBSONIntegers(t *testing.T) {
+	var doc struct {
+		Price Amount `bson:"price"`
+	}
+
+	data, _ := bson.Marshal(bson.M{"price": int64(12)})
+	if err := bson.Unmarshal(data, &doc); err != nil || doc.Price != MustParse("12") {
+		t.Errorf("decoded int64 12 as %s, %v", doc.Price, err)
+	}
+	data, _ = bson.Marshal(bson.M{"price": int32(-7)})
+	if err := bson.Unmarshal(data, &doc); err != nil || doc.Price != MustParse("-7") {
+		t.Errorf("decoded int32 -7 as %s, %v", doc.Price, err)
+	}
+
+	for _, value := range []int64{math.MaxInt64 / 1000, math.MinInt64 / 1000} {
+		data, _ := bson.Marshal(bson.M{"price": value})
+		if err := bson.Unmarshal(data, &doc); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("decoding int64 %d: err = %v, want ErrInvalidAmount", value, err)
+		}
+	}
+
+	var rate struct {
+		Rate Rate `bson:"rate"`
+	}
+	data, _ = bson.Marshal(bson.M{"rate": int32(math.MaxInt32)})
+	if err := bson.Unmarshal(data, &rate); err != nil || rate.Rate.String() != "2147483647" {
+		t.Errorf("decoded int32 rate as %s, %v", rate.Rate, err)
+	}
+	data, _ = bson.Marshal(bson.M{"rate": int64(math.MaxInt64 / 10)})
+	if err := bson.Unmarshal(data, &rate); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("decoding an out of range rate: err = %v, want ErrInvalidAmount", err)
+	}
+}
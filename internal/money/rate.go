@@ -0,0 +1,123 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// RateScale is the number of decimal places a Rate holds
+const RateScale = 8
+
+// RatePattern matches the string form of a Rate
+const RatePattern = `^[0-9]+(\.[0-9]{1,8})?$`
+
+// Rate is an exchange rate, the price of one unit of a currency in another,
+// held as an integer count of 10^-RateScale
+type Rate int64
+
+// ParseRate reads a positive decimal rate such as "0.92" or "149.8325"
+func ParseRate(s string) (Rate, error) {
+	value, err := parseFixed(s, RateScale)
+	if err == nil && value <= 0 {
+		err = fmt.Errorf("%w: rate %q is not positive", ErrInvalidAmount, s)
+	}
+	return Rate(value), err
+}
+
+// String writes the rate without trailing zero decimals
+func (r Rate) String() string {
+	text := strings.TrimRight(formatFixed(int64(r), RateScale, RateScale), "0")
+	return strings.TrimSuffix(text, ".")
+}
+
+// Decimal128 converts the rate for storage
+func (r Rate) Decimal128() primitive.Decimal128 {
+	value, _ := primitive.ParseDecimal128(formatFixed(int64(r), RateScale, RateScale))
+	return value
+}
+
+// Convert converts a into currency at rate, the price of one unit of a's
+// currency in currency
+func (a Amount) Convert(rate Rate, currency string) (Amount, error) {
+	return convert(a, big.NewInt(int64(rate)), big.NewInt(pow10(RateScale)), currency)
+}
+
+// ConvertInverse converts a into currency by dividing by rate, the price of
+// one unit of currency in a's currency
+func (a Amount) ConvertInverse(rate Rate, currency string) (Amount, error) {
+	return convert(a, big.NewInt(pow10(RateScale)), big.NewInt(int64(rate)), currency)
+}
+
+// convert returns a * numerator / denominator rounded half away from zero to
+// the minor unit of currency. The product is exact, so the only rounding is
+// the final one.
+func convert(a Amount, numerator, denominator *big.Int, currency string) (Amount, error) {
+	if denominator.Sign() <= 0 || numerator.Sign() <= 0 {
+		return 0, fmt.Errorf("%w: rate is not positive", ErrInvalidAmount)
+	}
+	step := big.NewInt(pow10(Scale - min(Decimals(currency), Scale)))
+
+	value := new(big.Int).Mul(big.NewInt(int64(a)), numerator)
+	divisor := new(big.Int).Mul(denominator, step)
+	quotient, remainder := new(big.Int).QuoRem(value, divisor, new(big.Int))
+	if remainder.Abs(remainder).Lsh(remainder, 1).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+	quotient.Mul(quotient, step)
+
+	if !quotient.IsInt64() {
+		return 0, fmt.Errorf("%w: %s converted to %s is out of range", ErrInvalidAmount, a, currency)
+	}
+	return Amount(quotient.Int64()), nil
+}
+
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalText(text []byte) error {
+	rate, err := ParseRate(string(text))
+	if err != nil {
+		return err
+	}
+	*r = rate
+	return nil
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a plain JSON number, read from
+// its text like an Amount, and leaves the rate unchanged for null
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	return r.UnmarshalText([]byte(text))
+}
+
+func (r Rate) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bsontype.Decimal128, bsoncore.AppendDecimal128(nil, r.Decimal128()), nil
+}
+
+func (r *Rate) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value, err := unmarshalFixed(t, data, RateScale)
+	if err != nil {
+		return err
+	}
+	*r = Rate(value)
+	return nil
+}
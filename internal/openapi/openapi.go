@@ -0,0 +1,268 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"rest-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the OpenAPI version of generated documents
+const Version = "3.1.0"
+
+// Envelope describes how a handler wraps its response body
+type Envelope int
+
+const (
+	// EnvelopeData wraps the body in models.APIResponse.data
+	EnvelopeData Envelope = iota
+	// EnvelopePage wraps a list of the body in models.PaginatedResponse.data
+	EnvelopePage
+	// EnvelopeNone sends the body as is
+	EnvelopeNone
+)
+
+const bearerAuth = "bearerAuth"
+
+// Operation documents one route. Method and Path must match the route as
+// registered with gin, e.g. "GET" and "/api/v1/products/:id".
+type Operation struct {
+	Method      string
+	Path        string
+	ID          string
+	Summary     string
+	Description string
+	Tag         string
+	Auth        bool
+	Query       []Parameter
+	// Request is a value of the request body type, or nil without a body
+	Request interface{}
+	// MergePatch accepts the body as an RFC 7396 merge patch
+	MergePatch bool
+	// Upload names the file field of a multipart/form-data request body
+	Upload string
+	Status int
+	// Response is a value of the response body type, or nil without data
+	Response interface{}
+	Envelope Envelope
+	// Page is a value of the page type wrapping an EnvelopePage response,
+	// models.PaginatedResponse when nil
+	Page        interface{}
+	ContentType string
+	// Alternate lists other statuses sent with the same body type
+	Alternate []int
+	// Errors lists the error statuses, sent as models.APIResponse or
+	// models.ProblemDetails
+	Errors []int
+}
+
+// PageParams are the query parameters accepted by paginated routes
+var PageParams = []Parameter{
+	{Name: "page", In: "query", Description: "Page number, starting at 1", Schema: &Schema{Type: "integer", Minimum: float(1)}},
+	{Name: "limit", In: "query", Description: "Items per page", Schema: &Schema{Type: "integer", Minimum: float(1), Maximum: float(100)}},
+}
+
+// Generate builds the document describing ops
+func Generate(info Info, ops []Operation) *Document {
+	s := newSchemas()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Responses: make(map[string]*Response),
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "Token returned by POST /api/v1/auth/login"},
+			},
+		},
+	}
+
+	tags := make(map[string]bool)
+	for _, op := range ops {
+		path, params := convertPath(op.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+
+		operation := &OperationObject{
+			OperationID: op.ID,
+			Summary:     op.Summary,
+			Description: op.Description,
+			Parameters:  append(params, op.Query...),
+			Responses:   make(map[string]*Response),
+		}
+		if op.Tag != "" {
+			operation.Tags = []string{op.Tag}
+			tags[op.Tag] = true
+		}
+		if op.Auth {
+			operation.Security = []SecurityRequirement{{bearerAuth: {}}}
+		}
+
+		if op.Request != nil {
+			content := map[string]MediaType{"application/json": {Schema: s.of(op.Request)}}
+			if op.MergePatch {
+				content["application/merge-patch+json"] = content["application/json"]
+			}
+			operation.RequestBody = &RequestBody{Required: true, Content: content}
+		}
+		if op.Upload != "" {
+			operation.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+				"multipart/form-data": {Schema: &Schema{
+					Type:       "object",
+					Properties: map[string]*Schema{op.Upload: {Type: "string", Format: "binary"}},
+					Required:   []string{op.Upload},
+				}},
+			}}
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		operation.Responses[strconv.Itoa(status)] = successResponse(s, op, status)
+		for _, code := range op.Alternate {
+			operation.Responses[strconv.Itoa(code)] = successResponse(s, op, code)
+		}
+
+		for _, code := range op.Errors {
+			name := strings.ReplaceAll(http.StatusText(code), " ", "")
+			if doc.Components.Responses[name] == nil {
+				doc.Components.Responses[name] = errorResponse(s, code)
+			}
+			operation.Responses[strconv.Itoa(code)] = &Response{Ref: "#/components/responses/" + name}
+		}
+
+		doc.Paths[path][strings.ToLower(op.Method)] = operation
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	doc.Components.Schemas = s.components
+
+	return doc
+}
+
+// Operation returns the operation for a method and gin route path, or nil
+func (d *Document) Operation(method, ginPath string) *OperationObject {
+	path, _ := convertPath(ginPath)
+	return d.Paths[path][strings.ToLower(method)]
+}
+
+func successResponse(s *schemas, op Operation, status int) *Response {
+	response := &Response{Description: http.StatusText(status)}
+
+	var schema *Schema
+	switch op.Envelope {
+	case EnvelopeData:
+		schema = s.of(models.APIResponse{})
+		if op.Response != nil {
+			schema = &Schema{AllOf: []*Schema{schema, {
+				Type:       "object",
+				Properties: map[string]*Schema{"data": s.of(op.Response)},
+				Required:   []string{"data"},
+			}}}
+		}
+	case EnvelopePage:
+		page := op.Page
+		if page == nil {
+			page = models.PaginatedResponse{}
+		}
+		schema = &Schema{AllOf: []*Schema{s.of(page), {
+			Type:       "object",
+			Properties: map[string]*Schema{"data": {Type: "array", Items: s.of(op.Response)}},
+		}}}
+	case EnvelopeNone:
+		if op.Response != nil {
+			schema = s.of(op.Response)
+		}
+	}
+
+	if schema != nil {
+		contentType := op.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		response.Content = map[string]MediaType{contentType: {Schema: schema}}
+	}
+	return response
+}
+
+func errorResponse(s *schemas, status int) *Response {
+	response := &Response{
+		Description: http.StatusText(status),
+		Content: map[string]MediaType{
+			"application/json":         {Schema: s.of(models.APIResponse{})},
+			"application/problem+json": {Schema: s.of(models.ProblemDetails{})},
+		},
+	}
+	if status == http.StatusTooManyRequests {
+		response.Headers = map[string]Header{
+			"Retry-After": {Description: "Seconds until a request will be allowed", Schema: &Schema{Type: "integer"}},
+		}
+	}
+	return response
+}
+
+// convertPath turns a gin path into an OpenAPI path and its parameters;
+// parameters named id, or ending in _id, are ObjectIDs
+func convertPath(path string) (string, []Parameter) {
+	var params []Parameter
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+
+		name := segment[1:]
+		schema := &Schema{Type: "string"}
+		if name == "id" || strings.HasSuffix(name, "_id") {
+			schema.Pattern = ObjectIDPattern
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+		segments[i] = "{" + name + "}"
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// CheckRoutes fails when a route registered with gin is not documented by
+// ops, or an operation has no route
+func CheckRoutes(routes gin.RoutesInfo, ops []Operation) error {
+	documented := make(map[string]bool, len(ops))
+	var errs []error
+	for _, op := range ops {
+		key := op.Method + " " + op.Path
+		if documented[key] {
+			errs = append(errs, fmt.Errorf("%s is documented twice", key))
+		}
+		documented[key] = true
+	}
+
+	registered := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		if !documented[key] {
+			errs = append(errs, fmt.Errorf("route %s is not documented", key))
+		}
+	}
+	for _, op := range ops {
+		if key := op.Method + " " + op.Path; !registered[key] {
+			errs = append(errs, fmt.Errorf("documented operation %s has no route", key))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func float(value float64) *float64 {
+	return &value
+}
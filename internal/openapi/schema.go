@@ -0,0 +1,265 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"rest-api/internal/money"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ObjectIDPattern matches the hex form of a MongoDB ObjectID
+const ObjectIDPattern = "^[0-9a-fA-F]{24}$"
+
+// SlugPattern matches lowercase words joined by single hyphens
+const SlugPattern = "^[a-z0-9]+(-[a-z0-9]+)*$"
+
+// CurrencyPattern matches the form of an ISO 4217 currency code
+const CurrencyPattern = "^[A-Z]{3}$"
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+	amountType   = reflect.TypeOf(money.Amount(0))
+	rateType     = reflect.TypeOf(money.Rate(0))
+)
+
+// schemas converts Go types into JSON schemas, registering named structs
+// as components and referring to them by $ref
+type schemas struct {
+	components map[string]*Schema
+}
+
+func newSchemas() *schemas {
+	return &schemas{components: make(map[string]*Schema)}
+}
+
+// of returns the schema for the type of value
+func (s *schemas) of(value interface{}) *Schema {
+	return s.forType(reflect.TypeOf(value))
+}
+
+func (s *schemas) forType(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case objectIDType:
+		return &Schema{Type: "string", Pattern: ObjectIDPattern}
+	case amountType:
+		return &Schema{Type: "string", Format: "decimal", Pattern: money.Pattern}
+	case rateType:
+		return &Schema{Type: "string", Format: "decimal", Pattern: money.RatePattern}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(s.forType(t.Elem()))
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.forType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.forType(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		if _, ok := s.components[t.Name()]; !ok {
+			// Register before building so recursive types terminate
+			s.components[t.Name()] = &Schema{}
+			*s.components[t.Name()] = *s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	default:
+		// interface{} and anything else accepts any JSON value
+		return &Schema{}
+	}
+}
+
+func (s *schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.addFields(schema, t)
+	return schema
+}
+
+func (s *schemas) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty := jsonName(field)
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			s.addFields(schema, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldType := field.Type
+		isPointer := fieldType.Kind() == reflect.Pointer
+		if isPointer {
+			fieldType = fieldType.Elem()
+		}
+
+		property := s.forType(fieldType)
+		rules, hasRules := field.Tag.Lookup("validate")
+		required := applyRules(property, fieldType, rules)
+		if isPointer {
+			property = nullable(property)
+		}
+		schema.Properties[name] = property
+
+		// Request fields are required when validated as such; response
+		// fields whenever they are always serialized
+		if !isPointer && ((hasRules && required) || (!hasRules && !omitEmpty)) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	name, options, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(options, "omitempty")
+}
+
+// applyRules translates validator tags into schema constraints and reports
+// whether the field is required. Rules after "dive" apply to the items.
+func applyRules(schema *Schema, t reflect.Type, rules string) bool {
+	required := false
+	target, targetType := schema, t
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if target == schema {
+				required = true
+			}
+		case "dive":
+			if target.Items == nil {
+				return required
+			}
+			target, targetType = target.Items, targetType.Elem()
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "mongodb":
+			target.Pattern = ObjectIDPattern
+		case "slug":
+			target.Pattern = SlugPattern
+		case "iso4217":
+			target.Pattern = CurrencyPattern
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				target.Enum = append(target.Enum, enumValue(targetType, value))
+			}
+		case "min", "max", "len":
+			applyBound(target, targetType, name, param)
+		case "gt", "gte", "lt", "lte":
+			// Decimal strings cannot carry numeric bounds in a schema; the
+			// validator still checks them
+			if target.Type == "string" {
+				continue
+			}
+			if number, err := strconv.ParseFloat(param, 64); err == nil {
+				switch name {
+				case "gt":
+					target.ExclusiveMinimum = &number
+				case "gte":
+					target.Minimum = &number
+				case "lt":
+					target.ExclusiveMaximum = &number
+				case "lte":
+					target.Maximum = &number
+				}
+			}
+		}
+	}
+
+	return required
+}
+
+func applyBound(schema *Schema, t reflect.Type, rule, param string) {
+	number, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	size := int(number)
+
+	switch t.Kind() {
+	case reflect.String:
+		if rule != "max" {
+			schema.MinLength = &size
+		}
+		if rule != "min" {
+			schema.MaxLength = &size
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if rule != "max" {
+			schema.MinItems = &size
+		}
+		if rule != "min" {
+			schema.MaxItems = &size
+		}
+	default:
+		if rule != "max" {
+			schema.Minimum = &number
+		}
+		if rule != "min" {
+			schema.Maximum = &number
+		}
+	}
+}
+
+func enumValue(t reflect.Type, value string) interface{} {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return number
+		}
+	case reflect.Float32, reflect.Float64:
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	}
+	return value
+}
+
+// nullable allows null in addition to the values schema accepts
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{OneOf: []*Schema{schema, {Type: "null"}}}
+	}
+	if typeName, ok := schema.Type.(string); ok {
+		schema.Type = []string{typeName, "null"}
+		if schema.Enum != nil {
+			schema.Enum = append(schema.Enum, nil)
+		}
+	}
+	return schema
+}
@@ -0,0 +1,235 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Violation is a value that does not match its schema. Field is a dotted
+// path such as "items.0.quantity"; Param holds the rule's argument.
+type Violation struct {
+	Field string
+	Rule  string
+	Param string
+}
+
+// ValidationError reports every violation found in a request or response
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = fmt.Sprintf("%s: %s %s", v.Field, v.Rule, v.Param)
+	}
+	return "schema validation failed: " + strings.Join(parts, "; ")
+}
+
+var patterns sync.Map
+
+// Validate checks a decoded JSON value (as produced by encoding/json into
+// interface{}) against schema, resolving references in the document
+func (d *Document) Validate(schema *Schema, value interface{}, field string) []Violation {
+	var violations []Violation
+	d.validate(schema, value, field, &violations)
+	return violations
+}
+
+func (d *Document) validate(schema *Schema, value interface{}, field string, violations *[]Violation) {
+	if schema == nil {
+		return
+	}
+	add := func(rule, param string) {
+		*violations = append(*violations, Violation{Field: field, Rule: rule, Param: param})
+	}
+
+	if schema.Ref != "" {
+		d.validate(d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")], value, field, violations)
+		return
+	}
+	for _, sub := range schema.AllOf {
+		d.validate(sub, value, field, violations)
+	}
+	if len(schema.OneOf) > 0 {
+		matches := 0
+		for _, sub := range schema.OneOf {
+			if len(d.Validate(sub, value, field)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			add("oneOf", "")
+		}
+		return
+	}
+
+	if types := typeNames(schema.Type); len(types) > 0 && !matchesType(types, value) {
+		add("type", strings.Join(types, ","))
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		values := make([]string, len(schema.Enum))
+		for i, v := range schema.Enum {
+			values[i] = fmt.Sprint(v)
+		}
+		add("enum", strings.Join(values, " "))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				*violations = append(*violations, Violation{Field: join(field, name), Rule: "required"})
+			}
+		}
+		for name, item := range v {
+			if property, ok := schema.Properties[name]; ok {
+				d.validate(property, item, join(field, name), violations)
+			} else if schema.AdditionalProperties != nil {
+				d.validate(schema.AdditionalProperties, item, join(field, name), violations)
+			}
+		}
+
+	case []interface{}:
+		if schema.MinItems != nil && len(v) < *schema.MinItems {
+			add("minItems", strconv.Itoa(*schema.MinItems))
+		}
+		if schema.MaxItems != nil && len(v) > *schema.MaxItems {
+			add("maxItems", strconv.Itoa(*schema.MaxItems))
+		}
+		for i, item := range v {
+			d.validate(schema.Items, item, join(field, strconv.Itoa(i)), violations)
+		}
+
+	case string:
+		length := utf8.RuneCountInString(v)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			add("minLength", strconv.Itoa(*schema.MinLength))
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			add("maxLength", strconv.Itoa(*schema.MaxLength))
+		}
+		if schema.Pattern != "" && !matchesPattern(schema.Pattern, v) {
+			add("pattern", schema.Pattern)
+		}
+		if schema.Format != "" && !matchesFormat(schema.Format, v) {
+			add("format", schema.Format)
+		}
+
+	case float64:
+		if schema.Minimum != nil && v < *schema.Minimum {
+			add("minimum", formatNumber(*schema.Minimum))
+		}
+		if schema.Maximum != nil && v > *schema.Maximum {
+			add("maximum", formatNumber(*schema.Maximum))
+		}
+		if schema.ExclusiveMinimum != nil && v <= *schema.ExclusiveMinimum {
+			add("exclusiveMinimum", formatNumber(*schema.ExclusiveMinimum))
+		}
+		if schema.ExclusiveMaximum != nil && v >= *schema.ExclusiveMaximum {
+			add("exclusiveMaximum", formatNumber(*schema.ExclusiveMaximum))
+		}
+	}
+}
+
+func typeNames(t interface{}) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	}
+	return nil
+}
+
+func matchesType(types []string, value interface{}) bool {
+	for _, t := range types {
+		switch v := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		switch a := allowed.(type) {
+		case int64:
+			if v, ok := value.(float64); ok && v == float64(a) {
+				return true
+			}
+		default:
+			if allowed == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesPattern(pattern, value string) bool {
+	compiled, ok := patterns.Load(pattern)
+	if !ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return true
+		}
+		compiled, _ = patterns.LoadOrStore(pattern, re)
+	}
+	return compiled.(*regexp.Regexp).MatchString(value)
+}
+
+func matchesFormat(format, value string) bool {
+	switch format {
+	case "email":
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	}
+	return true
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	fullAt  time.Time
+}
+
+// MemoryStore keeps token buckets in process memory. Idle buckets are
+// removed by a background cleanup loop until Close is called.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+	done    chan struct{}
+	once    sync.Once
+}
+
+func NewMemoryStore(cleanupInterval time.Duration) *MemoryStore {
+	store := &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+		done:    make(chan struct{}),
+	}
+
+	if cleanupInterval > 0 {
+		go store.cleanupLoop(cleanupInterval)
+	}
+
+	return store
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	now := s.now()
+	capacity := float64(limit.Capacity())
+	rate := limit.rate()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+
+	// Refill tokens for the time elapsed since the last request
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	b.updated = now
+
+	result := Result{Limit: limit.Capacity()}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / rate)
+	}
+
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = secondsToDuration((capacity - b.tokens) / rate)
+	b.fullAt = now.Add(result.Reset)
+
+	return result, nil
+}
+
+// Close stops the cleanup loop
+func (s *MemoryStore) Close() error {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+func (s *MemoryStore) cleanupLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.cleanup()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// cleanup removes buckets that have refilled completely, since a new
+// bucket would be in the same state
+func (s *MemoryStore) cleanup() {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if !now.Before(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit describes a token bucket that refills Requests tokens every Period
+// and holds at most Burst tokens. A zero Limit disables rate limiting.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// Enabled reports whether the limit should be enforced
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// Capacity returns the maximum number of tokens in the bucket
+func (l Limit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// rate returns the refill rate in tokens per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	if l.Burst > 0 {
+		return fmt.Sprintf("%d/%s:%d", l.Requests, l.Period, l.Burst)
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// ParseLimit parses limits written as "<requests>/<period>[:<burst>]",
+// e.g. "10/1m", "100/h" or "5/30s:20". "off" and "0" disable the limit.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "off" || value == "0" {
+		return Limit{}, nil
+	}
+
+	spec, rawBurst, hasBurst := strings.Cut(value, ":")
+	burst := 0
+	if hasBurst {
+		var err error
+		burst, err = strconv.Atoi(rawBurst)
+		if err != nil || burst <= 0 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: bad burst", value)
+		}
+	}
+
+	parts := strings.SplitN(spec, "/", 2)
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected <requests>/<period>[:<burst>]", value)
+	}
+
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil || requests < 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: bad request count", value)
+	}
+
+	period := parts[1]
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: bad period", value)
+	}
+
+	return Limit{Requests: requests, Period: duration, Burst: burst}, nil
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
+
+// Store keeps token buckets. Implementations backed by a shared store such
+// as Redis let several API instances enforce the same limits.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
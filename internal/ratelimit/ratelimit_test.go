@@ -0,0 +1,30 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Limit
+	}{
+		{"off", Limit{}},
+		{"10/1m", Limit{Requests: 10, Period: time.Minute}},
+		{"100/h", Limit{Requests: 100, Period: time.Hour}},
+		{"100/m:20", Limit{Requests: 100, Period: time.Minute, Burst: 20}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, %v; want %+v", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"10", "10/1m:", "10/1m:0", "10/1m:x", "x/1m"} {
+		if _, err := ParseLimit(value); err == nil {
+			t.Errorf("ParseLimit(%q) succeeded", value)
+		}
+	}
+}
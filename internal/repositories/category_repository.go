@@ -0,0 +1,234 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/metrics"
+	"rest-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RootPath is the materialized path of the virtual root above every
+// top-level category
+const RootPath = "/"
+
+type CategoryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCategoryRepository(db *mongo.Database) *CategoryRepository {
+	return &CategoryRepository{collection: db.Collection("categories")}
+}
+
+// Create inserts category as a child of the category at parentPath, or of
+// RootPath for a top-level category
+func (r *CategoryRepository) Create(ctx context.Context, category *models.Category, parentPath string) error {
+	defer metrics.ObserveDB("categories", "Create")()
+
+	category.ID = primitive.NewObjectID()
+	category.Path = parentPath + category.ID.Hex() + "/"
+	category.Depth = pathDepth(category.Path)
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, category)
+	if mongo.IsDuplicateKeyError(err) {
+		return apperrors.Conflict(i18n.MsgCategorySlugTaken)
+	}
+	return err
+}
+
+func (r *CategoryRepository) GetByID(ctx context.Context, id string) (*models.Category, error) {
+	defer metrics.ObserveDB("categories", "GetByID")()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.InvalidID(err)
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	defer metrics.ObserveDB("categories", "GetBySlug")()
+
+	return r.findOne(ctx, bson.M{"slug": slug})
+}
+
+// GetAll returns every category in tree order, each parent before its
+// subcategories
+func (r *CategoryRepository) GetAll(ctx context.Context) ([]models.Category, error) {
+	defer metrics.ObserveDB("categories", "GetAll")()
+
+	return r.find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "path", Value: 1}}))
+}
+
+// GetByIDs returns the categories with the given IDs that exist, in no
+// particular order
+func (r *CategoryRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Category, error) {
+	defer metrics.ObserveDB("categories", "GetByIDs")()
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+// SubtreeIDs returns the IDs of category and all of its descendants
+func (r *CategoryRepository) SubtreeIDs(ctx context.Context, category *models.Category) ([]primitive.ObjectID, error) {
+	defer metrics.ObserveDB("categories", "SubtreeIDs")()
+
+	cursor, err := r.collection.Find(ctx, subtreeFilter(category.Path), options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ids []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+	}
+	return ids, cursor.Err()
+}
+
+// HasChildren reports whether any category has id as its parent
+func (r *CategoryRepository) HasChildren(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	defer metrics.ObserveDB("categories", "HasChildren")()
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"parent_id": id}, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	defer metrics.ObserveDB("categories", "Update")()
+
+	category.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": category.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"name":        category.Name,
+			"slug":        category.Slug,
+			"description": category.Description,
+			"updated_at":  category.UpdatedAt,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return apperrors.Conflict(i18n.MsgCategorySlugTaken)
+	}
+	return err
+}
+
+// Move makes category a child of parent, or a top-level category when
+// parent is nil, rewriting the paths of its whole subtree
+func (r *CategoryRepository) Move(ctx context.Context, category *models.Category, parent *models.Category) error {
+	defer metrics.ObserveDB("categories", "Move")()
+
+	parentPath := RootPath
+	var parentID *primitive.ObjectID
+	if parent != nil {
+		parentPath = parent.Path
+		parentID = &parent.ID
+	}
+	oldPath := category.Path
+	newPath := parentPath + category.ID.Hex() + "/"
+
+	// Replace the old path prefix of every category in the subtree
+	_, err := r.collection.UpdateMany(ctx, subtreeFilter(oldPath), mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"path": bson.M{"$concat": bson.A{
+				newPath,
+				bson.M{"$substrBytes": bson.A{"$path", len(oldPath), bson.M{"$strLenBytes": "$path"}}},
+			}},
+			"depth": bson.M{"$add": bson.A{"$depth", pathDepth(newPath) - pathDepth(oldPath)}},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	category.ParentID = parentID
+	category.Path = newPath
+	category.Depth = pathDepth(newPath)
+	category.UpdatedAt = time.Now()
+
+	update := bson.M{"$set": bson.M{"updated_at": category.UpdatedAt}}
+	if parentID != nil {
+		update["$set"].(bson.M)["parent_id"] = *parentID
+	} else {
+		update["$unset"] = bson.M{"parent_id": ""}
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": category.ID}, update)
+	return err
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	defer metrics.ObserveDB("categories", "Delete")()
+
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *CategoryRepository) findOne(ctx context.Context, filter bson.M) (*models.Category, error) {
+	var category models.Category
+	err := r.collection.FindOne(ctx, filter).Decode(&category)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperrors.NotFound(i18n.MsgCategoryNotFound)
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]models.Category, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var categories []models.Category
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// subtreeFilter matches the category at path and all of its descendants
+func subtreeFilter(path string) bson.M {
+	return bson.M{"path": bson.M{"$regex": "^" + regexp.QuoteMeta(path)}}
+}
+
+// pathDepth returns the depth of a category path, 0 for top-level ones
+func pathDepth(path string) int {
+	return strings.Count(path, "/") - 2
+}
+
+// PathIDs returns the IDs in a category path from the root down
+func PathIDs(path string) []primitive.ObjectID {
+	var ids []primitive.ObjectID
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if id, err := primitive.ObjectIDFromHex(part); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
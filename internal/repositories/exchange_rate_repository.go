@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/metrics"
+	"rest-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ExchangeRateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewExchangeRateRepository(db *mongo.Database) *ExchangeRateRepository {
+	return &ExchangeRateRepository{collection: db.Collection("exchange_rates")}
+}
+
+// GetAll returns every rate ordered by base and quote currency
+func (r *ExchangeRateRepository) GetAll(ctx context.Context) ([]models.ExchangeRate, error) {
+	defer metrics.ObserveDB("exchange_rates", "GetAll")()
+
+	return r.find(ctx, bson.M{})
+}
+
+// GetByCurrency returns the rates quoted from or into currency
+func (r *ExchangeRateRepository) GetByCurrency(ctx context.Context, currency string) ([]models.ExchangeRate, error) {
+	defer metrics.ObserveDB("exchange_rates", "GetByCurrency")()
+
+	return r.find(ctx, bson.M{"$or": bson.A{bson.M{"base": currency}, bson.M{"quote": currency}}})
+}
+
+// Set inserts or replaces the rate of its currency pair, filling in the
+// stored ID and timestamps
+func (r *ExchangeRateRepository) Set(ctx context.Context, rate *models.ExchangeRate) error {
+	defer metrics.ObserveDB("exchange_rates", "Set")()
+
+	now := time.Now()
+	filter := bson.M{"base": rate.Base, "quote": rate.Quote}
+	update := bson.M{
+		"$set":         bson.M{"rate": rate.Rate, "updated_at": now},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	return r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(rate)
+}
+
+// Delete removes the rate of a currency pair
+func (r *ExchangeRateRepository) Delete(ctx context.Context, base, quote string) error {
+	defer metrics.ObserveDB("exchange_rates", "Delete")()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"base": base, "quote": quote})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return apperrors.NotFound(i18n.MsgExchangeRateNotFound)
+	}
+	return nil
+}
+
+func (r *ExchangeRateRepository) find(ctx context.Context, filter bson.M) ([]models.ExchangeRate, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "base", Value: 1}, {Key: "quote", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rates []models.ExchangeRate
+	if err := cursor.All(ctx, &rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
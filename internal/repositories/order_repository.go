@@ -1,62 +1,95 @@
 package repositories
 
 import (
+	"context"
+	"errors"
+	"time"
+
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/metrics"
 	"rest-api/internal/models"
 
-	"gorm.io/gorm"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type OrderRepository struct {
-	db *gorm.DB
+	collection *mongo.Collection
 }
 
-func NewOrderRepository(db *gorm.DB) *OrderRepository {
-	return &OrderRepository{db: db}
+func NewOrderRepository(db *mongo.Database) *OrderRepository {
+	return &OrderRepository{collection: db.Collection("orders")}
 }
 
-func (r *OrderRepository) Create(order *models.Order) error {
-	return r.db.Create(order).Error
+func (r *OrderRepository) Create(ctx context.Context, order *models.Order) error {
+	defer metrics.ObserveDB("orders", "Create")()
+
+	now := time.Now()
+	order.ID = primitive.NewObjectID()
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	for i := range order.OrderItems {
+		order.OrderItems[i].ID = primitive.NewObjectID()
+		order.OrderItems[i].CreatedAt = now
+		order.OrderItems[i].UpdatedAt = now
+	}
+
+	_, err := r.collection.InsertOne(ctx, order)
+	return err
 }
 
-func (r *OrderRepository) GetByID(id uint) (*models.Order, error) {
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	defer metrics.ObserveDB("orders", "GetByID")()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.InvalidID(err)
+	}
+
 	var order models.Order
-	err := r.db.Preload("User").Preload("OrderItems").Preload("OrderItems.Product").First(&order, id).Error
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&order)
 	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperrors.NotFound(i18n.MsgOrderNotFound)
+		}
 		return nil, err
 	}
 	return &order, nil
 }
 
-func (r *OrderRepository) GetAll(offset, limit int) ([]models.Order, int64, error) {
-	var orders []models.Order
-	var total int64
+// GetByUserID returns a page of the user's orders, newest first
+func (r *OrderRepository) GetByUserID(ctx context.Context, userID string, offset, limit int) ([]models.Order, int64, error) {
+	defer metrics.ObserveDB("orders", "GetByUserID")()
 
-	err := r.db.Model(&models.Order{}).Count(&total).Error
+	objID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, apperrors.InvalidID(err)
 	}
 
-	err = r.db.Preload("User").Preload("OrderItems").Preload("OrderItems.Product").Offset(offset).Limit(limit).Find(&orders).Error
-	return orders, total, err
-}
+	filter := bson.M{"user_id": objID}
 
-func (r *OrderRepository) GetByUserID(userID uint, offset, limit int) ([]models.Order, int64, error) {
-	var orders []models.Order
-	var total int64
-
-	err := r.db.Model(&models.Order{}).Where("user_id = ?", userID).Count(&total).Error
+	total, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	err = r.db.Preload("User").Preload("OrderItems").Preload("OrderItems.Product").Where("user_id = ?", userID).Offset(offset).Limit(limit).Find(&orders).Error
-	return orders, total, err
-}
+	opts := options.Find()
+	opts.SetSkip(int64(offset))
+	opts.SetLimit(int64(limit))
+	opts.SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
 
-func (r *OrderRepository) UpdateStatus(id uint, status string) error {
-	return r.db.Model(&models.Order{}).Where("id = ?", id).Update("status", status).Error
-}
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
 
-func (r *OrderRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Order{}, id).Error
+	var orders []models.Order
+	if err = cursor.All(ctx, &orders); err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
 }
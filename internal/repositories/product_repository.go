@@ -3,9 +3,15 @@ package repositories
 import (
 	"context"
 	"errors"
+	"strconv"
 	"time"
 
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/logger"
+	"rest-api/internal/metrics"
 	"rest-api/internal/models"
+	"rest-api/internal/money"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -25,88 +31,277 @@ func NewProductRepository(db *mongo.Database, userRepo *UserRepository) *Product
 	}
 }
 
-func (r *ProductRepository) Create(product *models.Product) error {
+func (r *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	defer metrics.ObserveDB("products", "Create")()
+
 	product.ID = primitive.NewObjectID()
 	product.CreatedAt = time.Now()
 	product.UpdatedAt = time.Now()
 
-	_, err := r.collection.InsertOne(context.TODO(), product)
+	_, err := r.collection.InsertOne(ctx, product)
+	if mongo.IsDuplicateKeyError(err) {
+		return apperrors.Conflict(i18n.MsgSKUTaken)
+	}
 	return err
 }
 
-func (r *ProductRepository) GetByID(id string) (*models.Product, error) {
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	defer metrics.ObserveDB("products", "GetByID")()
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.InvalidID(err)
 	}
 
 	var product models.Product
-	err = r.collection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&product)
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&product)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, errors.New("product not found")
+			return nil, apperrors.NotFound(i18n.MsgProductNotFound)
 		}
 		return nil, err
 	}
 
 	// Load user data
-	if !product.UserID.IsZero() {
-		user, err := r.userRepo.GetByID(product.UserID.Hex())
-		if err == nil {
-			product.User = *user
+	r.loadUser(ctx, &product)
+
+	return &product, nil
+}
+
+// ProductFilter narrows a product listing; zero values match everything
+type ProductFilter struct {
+	// CategoryIDs matches products assigned to any of the categories
+	CategoryIDs []primitive.ObjectID
+	// Tags matches products having all of the tags
+	Tags []string
+	// Prices matches products priced within the range for their currency;
+	// products in currencies without a range are left out
+	Prices []PriceRange
+	// InStock matches products with stock when true, without when false
+	InStock *bool
+}
+
+// PriceRange bounds the prices of products in one currency, inclusively;
+// a nil bound leaves that end open
+type PriceRange struct {
+	Currency string
+	Min      *money.Amount
+	Max      *money.Amount
+}
+
+func (f ProductFilter) query() bson.M {
+	filter := bson.M{}
+	if len(f.CategoryIDs) > 0 {
+		filter["category_ids"] = bson.M{"$in": f.CategoryIDs}
+	}
+	if len(f.Tags) > 0 {
+		filter["tags"] = bson.M{"$all": f.Tags}
+	}
+	if len(f.Prices) > 0 {
+		ranges := make(bson.A, len(f.Prices))
+		for i, priceRange := range f.Prices {
+			match := bson.M{"currency": priceRange.Currency}
+			price := bson.M{}
+			if priceRange.Min != nil {
+				price["$gte"] = *priceRange.Min
+			}
+			if priceRange.Max != nil {
+				price["$lte"] = *priceRange.Max
+			}
+			if len(price) > 0 {
+				match["price"] = price
+			}
+			ranges[i] = match
+		}
+		filter["$or"] = ranges
+	}
+	if f.InStock != nil {
+		if *f.InStock {
+			filter["stock"] = bson.M{"$gt": 0}
+		} else {
+			filter["stock"] = bson.M{"$lte": 0}
 		}
 	}
+	return filter
+}
 
-	return &product, nil
+// PriceBuckets are the lower bounds of the price ranges counted by Search
+// in each currency; the last range has no upper bound
+var PriceBuckets = []money.Amount{
+	money.MustParse("0"), money.MustParse("10"), money.MustParse("50"),
+	money.MustParse("100"), money.MustParse("500"), money.MustParse("1000"),
 }
 
-func (r *ProductRepository) GetAll(offset, limit int) ([]models.Product, int64, error) {
-	// Get total count
-	total, err := r.collection.CountDocuments(context.TODO(), bson.M{})
-	if err != nil {
-		return nil, 0, err
+// facetLimit caps the number of tags and categories counted by Search
+const facetLimit = 50
+
+// ProductSearchResult is a page of products with facet counts over every
+// product matching the filter
+type ProductSearchResult struct {
+	Products []models.Product
+	Total    int64
+	Facets   ProductFacetCounts
+}
+
+type ProductFacetCounts struct {
+	// Tags and Categories are ordered by descending count
+	Tags       []TagCount
+	Categories []CategoryCount
+	// PriceRanges omits empty ranges and is ordered by currency and price
+	PriceRanges []PriceRangeCount
+	InStock     int64
+	OutOfStock  int64
+}
+
+type TagCount struct {
+	Tag   string `bson:"_id"`
+	Count int64  `bson:"count"`
+}
+
+type CategoryCount struct {
+	CategoryID primitive.ObjectID `bson:"_id"`
+	Count      int64              `bson:"count"`
+}
+
+// PriceRangeCount counts the products priced in Currency in the range
+// starting at Min, one of PriceBuckets
+type PriceRangeCount struct {
+	Currency string
+	Min      money.Amount
+	Count    int64
+}
+
+// priceBucket is an aggregation expression for the lower bound of the
+// PriceBuckets range holding a product's price
+func priceBucket() bson.M {
+	branches := make(bson.A, 0, len(PriceBuckets)-1)
+	for i := len(PriceBuckets) - 1; i > 0; i-- {
+		branches = append(branches, bson.M{
+			"case": bson.M{"$gte": bson.A{"$price", PriceBuckets[i]}},
+			"then": PriceBuckets[i],
+		})
 	}
+	return bson.M{"$switch": bson.M{"branches": branches, "default": PriceBuckets[0]}}
+}
 
-	// Get products with pagination
-	opts := options.Find()
-	opts.SetSkip(int64(offset))
-	opts.SetLimit(int64(limit))
-	opts.SetSort(bson.D{primitive.E{Key: "created_at", Value: -1}})
+// Search returns a page of the products matching productFilter along with
+// the facet counts, in a single $facet aggregation
+func (r *ProductRepository) Search(ctx context.Context, productFilter ProductFilter, offset, limit int) (*ProductSearchResult, error) {
+	defer metrics.ObserveDB("products", "Search")()
+
+	countBy := func(field string) bson.A {
+		return bson.A{
+			bson.M{"$unwind": "$" + field},
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			bson.M{"$limit": facetLimit},
+		}
+	}
 
-	cursor, err := r.collection.Find(context.TODO(), bson.M{}, opts)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: productFilter.query()}},
+		{{Key: "$facet", Value: bson.M{
+			"items": bson.A{
+				bson.M{"$sort": bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+				bson.M{"$skip": offset},
+				bson.M{"$limit": limit},
+			},
+			"total":      bson.A{bson.M{"$count": "count"}},
+			"tags":       countBy("tags"),
+			"categories": countBy("category_ids"),
+			// Amounts in different currencies are not comparable, so each
+			// currency is bucketed on its own
+			"prices": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":   bson.D{{Key: "currency", Value: "$currency"}, {Key: "min", Value: priceBucket()}},
+					"count": bson.M{"$sum": 1},
+				}},
+				bson.M{"$sort": bson.D{{Key: "_id.currency", Value: 1}, {Key: "_id.min", Value: 1}}},
+			},
+			"availability": bson.A{
+				bson.M{"$group": bson.M{"_id": bson.M{"$gt": bson.A{"$stock", 0}}, "count": bson.M{"$sum": 1}}},
+			},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Items []models.Product `bson:"items"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Tags       []TagCount      `bson:"tags"`
+		Categories []CategoryCount `bson:"categories"`
+		Prices     []struct {
+			Range struct {
+				Currency string       `bson:"currency"`
+				Min      money.Amount `bson:"min"`
+			} `bson:"_id"`
+			Count int64 `bson:"count"`
+		} `bson:"prices"`
+		Availability []struct {
+			InStock bool  `bson:"_id"`
+			Count   int64 `bson:"count"`
+		} `bson:"availability"`
+	}
+	if err = cursor.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+	if len(facets) == 0 {
+		return &ProductSearchResult{}, nil
 	}
-	defer cursor.Close(context.TODO())
 
-	var products []models.Product
-	if err = cursor.All(context.TODO(), &products); err != nil {
-		return nil, 0, err
+	f := facets[0]
+	result := &ProductSearchResult{
+		Products: f.Items,
+		Facets: ProductFacetCounts{
+			Tags:       f.Tags,
+			Categories: f.Categories,
+		},
+	}
+	for _, price := range f.Prices {
+		result.Facets.PriceRanges = append(result.Facets.PriceRanges, PriceRangeCount{
+			Currency: price.Range.Currency,
+			Min:      price.Range.Min,
+			Count:    price.Count,
+		})
+	}
+	if len(f.Total) > 0 {
+		result.Total = f.Total[0].Count
+	}
+	for _, availability := range f.Availability {
+		if availability.InStock {
+			result.Facets.InStock = availability.Count
+		} else {
+			result.Facets.OutOfStock = availability.Count
+		}
 	}
 
 	// Load user data for each product
-	for i := range products {
-		if !products[i].UserID.IsZero() {
-			user, err := r.userRepo.GetByID(products[i].UserID.Hex())
-			if err == nil {
-				products[i].User = *user
-			}
-		}
+	for i := range result.Products {
+		r.loadUser(ctx, &result.Products[i])
 	}
 
-	return products, total, nil
+	return result, nil
 }
 
-func (r *ProductRepository) GetByUserID(userID string, offset, limit int) ([]models.Product, int64, error) {
+func (r *ProductRepository) GetByUserID(ctx context.Context, userID string, offset, limit int) ([]models.Product, int64, error) {
+	defer metrics.ObserveDB("products", "GetByUserID")()
+
 	objID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, apperrors.InvalidID(err)
 	}
 
 	filter := bson.M{"user_id": objID}
 
 	// Get total count
-	total, err := r.collection.CountDocuments(context.TODO(), filter)
+	total, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -117,62 +312,295 @@ func (r *ProductRepository) GetByUserID(userID string, offset, limit int) ([]mod
 	opts.SetLimit(int64(limit))
 	opts.SetSort(bson.D{primitive.E{Key: "created_at", Value: -1}})
 
-	cursor, err := r.collection.Find(context.TODO(), filter, opts)
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	var products []models.Product
-	if err = cursor.All(context.TODO(), &products); err != nil {
+	if err = cursor.All(ctx, &products); err != nil {
 		return nil, 0, err
 	}
 
 	// Load user data for each product
 	for i := range products {
-		if !products[i].UserID.IsZero() {
-			user, err := r.userRepo.GetByID(products[i].UserID.Hex())
-			if err == nil {
-				products[i].User = *user
-			}
-		}
+		r.loadUser(ctx, &products[i])
 	}
 
 	return products, total, nil
 }
 
-func (r *ProductRepository) Update(product *models.Product) error {
+// Update writes the product as read by GetByID and then changed. It only
+// applies while updated_at is unchanged, as stock and variants are written
+// whole: stock reserved by an order since the read would be lost, so the
+// update fails with a conflict instead.
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product) error {
+	defer metrics.ObserveDB("products", "Update")()
+
+	filter := bson.M{"_id": product.ID, "updated_at": product.UpdatedAt}
 	product.UpdatedAt = time.Now()
 
-	filter := bson.M{"_id": product.ID}
 	update := bson.M{
 		"$set": bson.M{
 			"name":        product.Name,
 			"description": product.Description,
 			"price":       product.Price,
+			"currency":    product.Currency,
 			"stock":       product.Stock,
 			"updated_at":  product.UpdatedAt,
 		},
 	}
+	unset := bson.M{}
+	if len(product.CategoryIDs) > 0 {
+		update["$set"].(bson.M)["category_ids"] = product.CategoryIDs
+	} else {
+		unset["category_ids"] = ""
+	}
+	if len(product.Tags) > 0 {
+		update["$set"].(bson.M)["tags"] = product.Tags
+	} else {
+		unset["tags"] = ""
+	}
+	if len(product.Variants) > 0 {
+		update["$set"].(bson.M)["options"] = product.Options
+		update["$set"].(bson.M)["variants"] = product.Variants
+	} else {
+		unset["options"] = ""
+		unset["variants"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return apperrors.Conflict(i18n.MsgSKUTaken)
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return r.mismatch(ctx, product.ID, apperrors.Conflict(i18n.MsgProductModified))
+	}
+	return nil
+}
+
+// ReserveStock takes quantity from the stock of the product, and of the
+// variant when variantID is set, failing with a conflict when there is not
+// enough. The check and the decrement are a single update, so concurrent
+// orders cannot oversell.
+func (r *ProductRepository) ReserveStock(ctx context.Context, productID primitive.ObjectID, variantID *primitive.ObjectID, quantity int) error {
+	defer metrics.ObserveDB("products", "ReserveStock")()
+
+	filter := bson.M{"_id": productID}
+	inc := bson.M{"stock": -quantity}
+	if variantID != nil {
+		filter["variants"] = bson.M{"$elemMatch": bson.M{"_id": *variantID, "stock": bson.M{"$gte": quantity}}}
+		inc["variants.$.stock"] = -quantity
+	} else {
+		filter["stock"] = bson.M{"$gte": quantity}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$inc": inc, "$set": bson.M{"updated_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return r.mismatch(ctx, productID, apperrors.Conflict(i18n.MsgInsufficientStock))
+	}
+	return nil
+}
+
+// ReleaseStock returns stock taken by ReserveStock
+func (r *ProductRepository) ReleaseStock(ctx context.Context, productID primitive.ObjectID, variantID *primitive.ObjectID, quantity int) error {
+	defer metrics.ObserveDB("products", "ReleaseStock")()
 
-	_, err := r.collection.UpdateOne(context.TODO(), filter, update)
+	filter := bson.M{"_id": productID}
+	inc := bson.M{"stock": quantity}
+	if variantID != nil {
+		filter["variants._id"] = *variantID
+		inc["variants.$.stock"] = quantity
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$inc": inc, "$set": bson.M{"updated_at": time.Now()}})
 	return err
 }
 
-func (r *ProductRepository) Delete(id string) error {
-	objID, err := primitive.ObjectIDFromHex(id)
+// AddImage appends an image unless the product already has maxImages
+func (r *ProductRepository) AddImage(ctx context.Context, productID primitive.ObjectID, image models.ProductImage, maxImages int) error {
+	defer metrics.ObserveDB("products", "AddImage")()
+
+	filter := bson.M{"_id": productID, "images." + strconv.Itoa(maxImages-1): bson.M{"$exists": false}}
+	update := bson.M{
+		"$push": bson.M{"images": image},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return r.mismatch(ctx, productID, apperrors.Conflict(i18n.MsgProductImageLimit))
+	}
+	return nil
+}
+
+// ReorderImages puts the product's images in the order of imageIDs, which
+// must list each of them exactly once
+func (r *ProductRepository) ReorderImages(ctx context.Context, productID primitive.ObjectID, imageIDs []primitive.ObjectID) error {
+	defer metrics.ObserveDB("products", "ReorderImages")()
+
+	// Matching the size and every ID ensures the order is still a
+	// permutation of the images if they changed since they were read
+	filter := bson.M{
+		"_id":        productID,
+		"images":     bson.M{"$size": len(imageIDs)},
+		"images._id": bson.M{"$all": imageIDs},
+	}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"images": bson.M{"$map": bson.M{
+			"input": imageIDs,
+			"as":    "id",
+			"in": bson.M{"$arrayElemAt": bson.A{
+				bson.M{"$filter": bson.M{"input": "$images", "cond": bson.M{"$eq": bson.A{"$$this._id", "$$id"}}}},
+				0,
+			}},
+		}},
+		"updated_at": time.Now(),
+	}}}}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return r.mismatch(ctx, productID, apperrors.Conflict(i18n.MsgProductImageOrder))
+	}
+	return nil
+}
 
-	_, err = r.collection.DeleteOne(context.TODO(), bson.M{"_id": objID})
+// RemoveImage removes an image from the product
+func (r *ProductRepository) RemoveImage(ctx context.Context, productID, imageID primitive.ObjectID) error {
+	defer metrics.ObserveDB("products", "RemoveImage")()
+
+	filter := bson.M{"_id": productID, "images._id": imageID}
+	update := bson.M{
+		"$pull": bson.M{"images": bson.M{"_id": imageID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return r.mismatch(ctx, productID, apperrors.NotFound(i18n.MsgProductImageNotFound))
+	}
+	return nil
+}
+
+// mismatch explains a conditional update that matched nothing: either the
+// product is gone or it did not satisfy the condition
+func (r *ProductRepository) mismatch(ctx context.Context, productID primitive.ObjectID, mismatch error) error {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": productID}, options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return apperrors.NotFound(i18n.MsgProductNotFound)
+	}
+	return mismatch
+}
+
+// RemoveCategory unassigns a deleted category from every product
+func (r *ProductRepository) RemoveCategory(ctx context.Context, categoryID primitive.ObjectID) error {
+	defer metrics.ObserveDB("products", "RemoveCategory")()
+
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"category_ids": categoryID},
+		bson.M{"$pull": bson.M{"category_ids": categoryID}},
+	)
 	return err
 }
 
-func (r *ProductRepository) UpdateStock(id string, stock int) error {
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	defer metrics.ObserveDB("products", "Delete")()
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return err
+		return apperrors.InvalidID(err)
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	return err
+}
+
+// DeleteOrphaned removes products whose owner no longer exists, which are
+// left behind when users delete their accounts
+func (r *ProductRepository) DeleteOrphaned(ctx context.Context) (int64, error) {
+	orphaned, err := r.OrphanedOwners(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return r.DeleteByOwners(ctx, orphaned)
+}
+
+// OrphanedOwners returns the IDs of product owners that are not existing
+// users
+func (r *ProductRepository) OrphanedOwners(ctx context.Context) ([]primitive.ObjectID, error) {
+	defer metrics.ObserveDB("products", "OrphanedOwners")()
+
+	values, err := r.collection.Distinct(ctx, "user_id", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	ownerIDs := make([]primitive.ObjectID, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(primitive.ObjectID); ok {
+			ownerIDs = append(ownerIDs, id)
+		}
+	}
+
+	existing, err := r.userRepo.ExistingIDs(ctx, ownerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only owners seen here can be orphaned; users who register meanwhile
+	// are not among them
+	exists := make(map[primitive.ObjectID]bool, len(existing))
+	for _, id := range existing {
+		exists[id] = true
+	}
+	orphaned := make([]primitive.ObjectID, 0, len(ownerIDs))
+	for _, id := range ownerIDs {
+		if !exists[id] {
+			orphaned = append(orphaned, id)
+		}
+	}
+	return orphaned, nil
+}
+
+// DeleteByOwners removes the products of the given owners
+func (r *ProductRepository) DeleteByOwners(ctx context.Context, ownerIDs []primitive.ObjectID) (int64, error) {
+	defer metrics.ObserveDB("products", "DeleteByOwners")()
+
+	if len(ownerIDs) == 0 {
+		return 0, nil
+	}
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": bson.M{"$in": ownerIDs}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *ProductRepository) UpdateStock(ctx context.Context, id string, stock int) error {
+	defer metrics.ObserveDB("products", "UpdateStock")()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.InvalidID(err)
 	}
 
 	filter := bson.M{"_id": objID}
@@ -183,6 +611,24 @@ func (r *ProductRepository) UpdateStock(id string, stock int) error {
 		},
 	}
 
-	_, err = r.collection.UpdateOne(context.TODO(), filter, update)
+	_, err = r.collection.UpdateOne(ctx, filter, update)
 	return err
 }
+
+// loadUser fills in the product owner. A missing owner is logged but does
+// not fail the read.
+func (r *ProductRepository) loadUser(ctx context.Context, product *models.Product) {
+	defer metrics.ObserveDB("products", "loadUser")()
+
+	if product.UserID.IsZero() {
+		return
+	}
+
+	user, err := r.userRepo.GetByID(ctx, product.UserID.Hex())
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to load product owner",
+			"product_id", product.ID.Hex(), "user_id", product.UserID.Hex(), "error", err)
+		return
+	}
+	product.User = *user
+}
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"time"
 
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/metrics"
 	"rest-api/internal/models"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -23,45 +26,56 @@ func NewUserRepository(db *mongo.Database) *UserRepository {
 	}
 }
 
-func (r *UserRepository) Create(user *models.User) error {
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	defer metrics.ObserveDB("users", "Create")()
+
 	user.ID = primitive.NewObjectID()
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
-	_, err := r.collection.InsertOne(context.TODO(), user)
+	_, err := r.collection.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return apperrors.Conflict(i18n.MsgEmailExists)
+	}
 	return err
 }
 
-func (r *UserRepository) GetByID(id string) (*models.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	defer metrics.ObserveDB("users", "GetByID")()
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.InvalidID(err)
 	}
 
 	var user models.User
-	err = r.collection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&user)
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&user)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, errors.New("user not found")
+			return nil, apperrors.NotFound(i18n.MsgUserNotFound)
 		}
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	defer metrics.ObserveDB("users", "GetByEmail")()
+
 	var user models.User
-	err := r.collection.FindOne(context.TODO(), bson.M{"email": email}).Decode(&user)
+	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, errors.New("user not found")
+			return nil, apperrors.NotFound(i18n.MsgUserNotFound)
 		}
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) Update(user *models.User) error {
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	defer metrics.ObserveDB("users", "Update")()
+
 	user.UpdatedAt = time.Now()
 
 	filter := bson.M{"_id": user.ID}
@@ -69,27 +83,86 @@ func (r *UserRepository) Update(user *models.User) error {
 		"$set": bson.M{
 			"name":       user.Name,
 			"email":      user.Email,
+			"locale":     user.Locale,
 			"updated_at": user.UpdatedAt,
 		},
 	}
 
-	_, err := r.collection.UpdateOne(context.TODO(), filter, update)
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return apperrors.Conflict(i18n.MsgEmailTaken)
+	}
+	return err
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, hashedPassword string) error {
+	defer metrics.ObserveDB("users", "UpdatePassword")()
+
+	update := bson.M{
+		"$set": bson.M{
+			"password":   hashedPassword,
+			"updated_at": time.Now(),
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
 	return err
 }
 
-func (r *UserRepository) Delete(id string) error {
+func (r *UserRepository) UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error {
+	defer metrics.ObserveDB("users", "UpdateRole")()
+
+	update := bson.M{
+		"$set": bson.M{
+			"role":       role,
+			"updated_at": time.Now(),
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// ExistingIDs returns the subset of ids that belong to existing users
+func (r *UserRepository) ExistingIDs(ctx context.Context, ids []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	defer metrics.ObserveDB("users", "ExistingIDs")()
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	existing := make([]primitive.ObjectID, len(users))
+	for i, user := range users {
+		existing[i] = user.ID
+	}
+	return existing, nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	defer metrics.ObserveDB("users", "Delete")()
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return err
+		return apperrors.InvalidID(err)
 	}
 
-	_, err = r.collection.DeleteOne(context.TODO(), bson.M{"_id": objID})
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objID})
 	return err
 }
 
-func (r *UserRepository) GetAll(offset, limit int) ([]models.User, int64, error) {
+func (r *UserRepository) GetAll(ctx context.Context, offset, limit int) ([]models.User, int64, error) {
+	defer metrics.ObserveDB("users", "GetAll")()
+
 	// Get total count
-	total, err := r.collection.CountDocuments(context.TODO(), bson.M{})
+	total, err := r.collection.CountDocuments(ctx, bson.M{})
 	if err != nil {
 		return nil, 0, err
 	}
@@ -100,14 +173,14 @@ func (r *UserRepository) GetAll(offset, limit int) ([]models.User, int64, error)
 	opts.SetLimit(int64(limit))
 	opts.SetSort(bson.D{primitive.E{Key: "created_at", Value: -1}})
 
-	cursor, err := r.collection.Find(context.TODO(), bson.M{}, opts)
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	var users []models.User
-	if err = cursor.All(context.TODO(), &users); err != nil {
+	if err = cursor.All(ctx, &users); err != nil {
 		return nil, 0, err
 	}
 
@@ -0,0 +1,71 @@
+package responder
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"rest-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type defined by RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// write sends a failed response either as the APIResponse envelope or, when
+// the client asks for it, as an RFC 7807 problem document
+func write(c *gin.Context, status int, response models.APIResponse) {
+	response.RequestID = c.GetString("request_id")
+
+	if !acceptsProblem(c.GetHeader("Accept")) {
+		c.JSON(status, response)
+		return
+	}
+
+	problem := models.ProblemDetails{
+		Type:      problemType(response.Code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    response.Message,
+		Instance:  c.Request.URL.RequestURI(),
+		Code:      response.Code,
+		Errors:    response.Errors,
+		RequestID: response.RequestID,
+	}
+	// Field details are carried by errors, so a reason that repeats the
+	// message is not appended to it
+	if response.Error != "" && response.Error != response.Message {
+		problem.Detail = response.Message + ": " + response.Error
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(status, problem)
+}
+
+// acceptsProblem reports whether the Accept header lists problem+json with a
+// non-zero quality
+func acceptsProblem(accept string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil || mediaType != ProblemContentType {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if quality, err := strconv.ParseFloat(q, 64); err == nil && quality == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// problemType derives a stable problem type URI from an error code
+func problemType(code string) string {
+	if code == "" {
+		return "about:blank"
+	}
+	return "urn:problem-type:" + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
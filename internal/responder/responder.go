@@ -0,0 +1,93 @@
+package responder
+
+import (
+	"errors"
+	"net/http"
+
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/logger"
+	"rest-api/internal/models"
+	"rest-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error writes a failed APIResponse for err. Domain errors are mapped to
+// their HTTP status; anything else is reported as an internal error
+// without exposing its message.
+func Error(c *gin.Context, messageKey string, err error) {
+	locale := c.GetString("locale")
+	status, code := statusFor(err)
+
+	response := models.APIResponse{
+		Success: false,
+		Message: i18n.T(locale, messageKey),
+		Code:    code,
+	}
+
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) && appErr.Key != "" {
+		response.Error = i18n.T(locale, appErr.Key)
+	} else if status == http.StatusInternalServerError {
+		response.Error = i18n.T(locale, i18n.MsgInternalServerError)
+	}
+
+	if errors.Is(err, apperrors.ErrValidation) || errors.Is(err, apperrors.ErrBadRequest) {
+		response.Errors = utils.FieldErrors(err, locale)
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.FromContext(c.Request.Context()).Error("internal error",
+			"method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
+	}
+
+	write(c, status, response)
+}
+
+// BindError writes the response for a request body that could not be
+// decoded. The decoder's message names Go types, so only the fields it
+// reports are passed on.
+func BindError(c *gin.Context, err error) {
+	locale := c.GetString("locale")
+	write(c, http.StatusBadRequest, models.APIResponse{
+		Success: false,
+		Message: i18n.T(locale, i18n.MsgInvalidRequestBody),
+		Error:   i18n.T(locale, i18n.MsgInvalidRequestBody),
+		Code:    models.ErrCodeInvalidRequestBody,
+		Errors:  utils.FieldErrors(err, locale),
+	})
+}
+
+// Fail writes a failed APIResponse for protocol-level errors that do not
+// originate from the service layer
+func Fail(c *gin.Context, status int, code, messageKey string) {
+	write(c, status, models.APIResponse{
+		Success: false,
+		Message: i18n.T(c.GetString("locale"), messageKey),
+		Code:    code,
+	})
+}
+
+func statusFor(err error) (int, string) {
+	switch {
+	case errors.Is(err, apperrors.ErrValidation):
+		return http.StatusUnprocessableEntity, models.ErrCodeValidationFailed
+	case errors.Is(err, apperrors.ErrBadRequest):
+		return http.StatusBadRequest, models.ErrCodeBadRequest
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return http.StatusUnauthorized, models.ErrCodeUnauthorized
+	case errors.Is(err, apperrors.ErrForbidden):
+		return http.StatusForbidden, models.ErrCodeForbidden
+	case errors.Is(err, apperrors.ErrNotFound):
+		return http.StatusNotFound, models.ErrCodeNotFound
+	case errors.Is(err, apperrors.ErrConflict):
+		return http.StatusConflict, models.ErrCodeConflict
+	case errors.Is(err, apperrors.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge, models.ErrCodePayloadTooLarge
+	case errors.Is(err, apperrors.ErrUnsupported):
+		return http.StatusUnsupportedMediaType, models.ErrCodeUnsupportedMediaType
+	default:
+		return http.StatusInternalServerError, models.ErrCodeInternal
+	}
+}
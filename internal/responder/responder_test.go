@@ -0,0 +1,72 @@
+package responder
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rest-api/internal/i18n"
+	"rest-api/internal/models"
+	"rest-api/internal/money"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBindErrorHidesDecoderMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var body struct {
+		Item struct {
+			Price money.Amount `json:"price"`
+			Stock int          `json:"stock"`
+		} `json:"item"`
+	}
+	tests := []struct {
+		name   string
+		body   string
+		errors []models.FieldError
+	}{
+		{"wrong type", `{"item": {"stock": "many"}}`, []models.FieldError{{
+			Field: "item.stock", Rule: "type", Param: "integer",
+			Message: i18n.T("en", i18n.MsgValidationType, "item.stock", "integer"),
+		}}},
+		{"bad amount", `{"item": {"price": "cheap"}}`, nil},
+		{"malformed", `{"item": `, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.body), &body)
+			if err == nil {
+				t.Fatal("body decoded without an error")
+			}
+
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			c.Set("locale", "en")
+			BindError(c, err)
+
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+			for _, leak := range []string{"money.Amount", "Go ", "struct", "json:"} {
+				if strings.Contains(recorder.Body.String(), leak) {
+					t.Errorf("response %s mentions %q", recorder.Body.String(), leak)
+				}
+			}
+
+			var response models.APIResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if want := i18n.T("en", i18n.MsgInvalidRequestBody); response.Error != want {
+				t.Errorf("error = %q, want %q", response.Error, want)
+			}
+			if len(response.Errors) != len(tt.errors) || (len(tt.errors) > 0 && response.Errors[0] != tt.errors[0]) {
+				t.Errorf("errors = %+v, want %+v", response.Errors, tt.errors)
+			}
+		})
+	}
+}
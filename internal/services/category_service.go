@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/logger"
+	"rest-api/internal/models"
+	"rest-api/internal/repositories"
+	"rest-api/internal/tracing"
+	"rest-api/internal/utils"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CategoryService struct {
+	categoryRepo *repositories.CategoryRepository
+	productRepo  *repositories.ProductRepository
+	validator    *validator.Validate
+}
+
+func NewCategoryService(categoryRepo *repositories.CategoryRepository, productRepo *repositories.ProductRepository, validator *validator.Validate) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+		validator:    validator,
+	}
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, req *models.CreateCategoryRequest) (*models.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.CreateCategory")
+	defer span.End()
+
+	if req.Slug == "" {
+		req.Slug = utils.Slugify(req.Name)
+	}
+	if err := s.validator.Struct(req); err != nil {
+		return nil, apperrors.Validation(err)
+	}
+	if req.Slug == "" {
+		// The name has no letters or digits to derive a slug from
+		req.Slug = primitive.NewObjectID().Hex()
+	}
+
+	parentPath := repositories.RootPath
+	category := &models.Category{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+	}
+	if req.ParentID != "" {
+		parent, err := s.parent(ctx, req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentID = &parent.ID
+		parentPath = parent.Path
+	}
+
+	if err := s.categoryRepo.Create(ctx, category, parentPath); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("category created", "category_id", category.ID.Hex(), "slug", category.Slug)
+
+	return s.categoryResponse(ctx, category)
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, id string) (*models.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetCategory")
+	defer span.End()
+
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.categoryResponse(ctx, category)
+}
+
+// ListCategories returns the whole taxonomy in tree order
+func (s *CategoryService) ListCategories(ctx context.Context) ([]models.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.ListCategories")
+	defer span.End()
+
+	categories, err := s.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]models.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	responses := make([]models.CategoryResponse, len(categories))
+	for i := range categories {
+		responses[i] = convertToCategoryResponse(&categories[i], breadcrumbs(byID, categories[i].Path))
+	}
+	return responses, nil
+}
+
+func (s *CategoryService) UpdateCategory(ctx context.Context, id string, req *models.UpdateCategoryRequest) (*models.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.UpdateCategory")
+	defer span.End()
+
+	if err := s.validator.Struct(req); err != nil {
+		return nil, apperrors.Validation(err)
+	}
+
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the move before changing anything
+	var parent *models.Category
+	moving := false
+	if req.ParentID != nil {
+		if parent, moving, err = s.moveTarget(ctx, category, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Name != "" {
+		category.Name = req.Name
+	}
+	if req.Slug != "" {
+		category.Slug = req.Slug
+	}
+	if req.Description != "" {
+		category.Description = req.Description
+	}
+
+	if err := s.categoryRepo.Update(ctx, category); err != nil {
+		return nil, err
+	}
+
+	if moving {
+		if err := s.categoryRepo.Move(ctx, category, parent); err != nil {
+			return nil, err
+		}
+		logger.FromContext(ctx).Info("category moved", "category_id", id, "parent_id", *req.ParentID)
+	}
+
+	return s.categoryResponse(ctx, category)
+}
+
+// DeleteCategory deletes a category without subcategories and removes it
+// from its products
+func (s *CategoryService) DeleteCategory(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "CategoryService.DeleteCategory")
+	defer span.End()
+
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	hasChildren, err := s.categoryRepo.HasChildren(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return apperrors.Conflict(i18n.MsgCategoryHasChildren)
+	}
+
+	if err := s.categoryRepo.Delete(ctx, category.ID); err != nil {
+		return err
+	}
+	if err := s.productRepo.RemoveCategory(ctx, category.ID); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("category deleted", "category_id", id)
+	return nil
+}
+
+// moveTarget resolves the new parent of category, nil for the top level
+// when parentID is empty, and reports whether it differs from the current
+// one. A category cannot move under itself or its descendants.
+func (s *CategoryService) moveTarget(ctx context.Context, category *models.Category, parentID string) (*models.Category, bool, error) {
+	currentParent := ""
+	if category.ParentID != nil {
+		currentParent = category.ParentID.Hex()
+	}
+	if parentID == currentParent {
+		return nil, false, nil
+	}
+	if parentID == "" {
+		return nil, true, nil
+	}
+
+	parent, err := s.parent(ctx, parentID)
+	if err != nil {
+		return nil, false, err
+	}
+	if strings.HasPrefix(parent.Path, category.Path) {
+		return nil, false, apperrors.Conflict(i18n.MsgCategoryCycle)
+	}
+	return parent, true, nil
+}
+
+// parent loads the parent named in a request, reporting a missing one as a
+// bad request rather than as a missing resource
+func (s *CategoryService) parent(ctx context.Context, id string) (*models.Category, error) {
+	parent, err := s.categoryRepo.GetByID(ctx, id)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, apperrors.BadRequest(i18n.MsgCategoryNotFound, err)
+	}
+	return parent, err
+}
+
+func (s *CategoryService) categoryResponse(ctx context.Context, category *models.Category) (*models.CategoryResponse, error) {
+	trails, err := loadBreadcrumbs(ctx, s.categoryRepo, []primitive.ObjectID{category.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	response := convertToCategoryResponse(category, trails[category.ID])
+	return &response, nil
+}
+
+// loadBreadcrumbs returns the trail from the root down to each of the
+// categories with the given IDs. Categories that no longer exist are left
+// out.
+func loadBreadcrumbs(ctx context.Context, categoryRepo *repositories.CategoryRepository, ids []primitive.ObjectID) (map[primitive.ObjectID][]models.CategorySummary, error) {
+	categories, err := categoryRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var ancestorIDs []primitive.ObjectID
+	for _, category := range categories {
+		ancestorIDs = append(ancestorIDs, repositories.PathIDs(category.Path)...)
+	}
+	ancestors, err := categoryRepo.GetByIDs(ctx, ancestorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]models.Category, len(ancestors))
+	for _, ancestor := range ancestors {
+		byID[ancestor.ID] = ancestor
+	}
+
+	trails := make(map[primitive.ObjectID][]models.CategorySummary, len(categories))
+	for _, category := range categories {
+		trails[category.ID] = breadcrumbs(byID, category.Path)
+	}
+	return trails, nil
+}
+
+// breadcrumbs builds the trail for a category path from the categories on it
+func breadcrumbs(byID map[primitive.ObjectID]models.Category, path string) []models.CategorySummary {
+	trail := []models.CategorySummary{}
+	for _, id := range repositories.PathIDs(path) {
+		if category, ok := byID[id]; ok {
+			trail = append(trail, models.CategorySummary{ID: category.ID.Hex(), Name: category.Name, Slug: category.Slug})
+		}
+	}
+	return trail
+}
+
+func convertToCategoryResponse(category *models.Category, trail []models.CategorySummary) models.CategoryResponse {
+	response := models.CategoryResponse{
+		ID:          category.ID.Hex(),
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		Depth:       category.Depth,
+		Breadcrumbs: trail,
+		CreatedAt:   category.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   category.UpdatedAt.Format(time.RFC3339),
+	}
+	if category.ParentID != nil {
+		response.ParentID = category.ParentID.Hex()
+	}
+	if response.Breadcrumbs == nil {
+		response.Breadcrumbs = []models.CategorySummary{}
+	}
+	return response
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/logger"
+	"rest-api/internal/models"
+	"rest-api/internal/money"
+	"rest-api/internal/repositories"
+	"rest-api/internal/tracing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ExchangeRateService struct {
+	rateRepo  *repositories.ExchangeRateRepository
+	validator *validator.Validate
+}
+
+func NewExchangeRateService(rateRepo *repositories.ExchangeRateRepository, validator *validator.Validate) *ExchangeRateService {
+	return &ExchangeRateService{
+		rateRepo:  rateRepo,
+		validator: validator,
+	}
+}
+
+func (s *ExchangeRateService) ListExchangeRates(ctx context.Context) ([]models.ExchangeRateResponse, error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateService.ListExchangeRates")
+	defer span.End()
+
+	rates, err := s.rateRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return exchangeRateResponses(rates), nil
+}
+
+// SetExchangeRate sets the price of one unit of base in quote, replacing
+// the pair's current rate. Orders placed earlier keep the rate they were
+// converted at.
+func (s *ExchangeRateService) SetExchangeRate(ctx context.Context, base, quote string, req *models.SetExchangeRateRequest) (*models.ExchangeRateResponse, error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateService.SetExchangeRate")
+	defer span.End()
+
+	if err := s.validator.Struct(req); err != nil {
+		return nil, apperrors.Validation(err)
+	}
+	if err := s.checkPair(base, quote); err != nil {
+		return nil, err
+	}
+
+	rate := &models.ExchangeRate{Base: base, Quote: quote, Rate: req.Rate}
+	if err := s.rateRepo.Set(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("exchange rate set", "base", base, "quote", quote, "rate", rate.Rate.String())
+
+	return &exchangeRateResponses([]models.ExchangeRate{*rate})[0], nil
+}
+
+func (s *ExchangeRateService) DeleteExchangeRate(ctx context.Context, base, quote string) error {
+	ctx, span := tracing.Start(ctx, "ExchangeRateService.DeleteExchangeRate")
+	defer span.End()
+
+	if err := s.checkPair(base, quote); err != nil {
+		return err
+	}
+	if err := s.rateRepo.Delete(ctx, base, quote); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("exchange rate deleted", "base", base, "quote", quote)
+	return nil
+}
+
+// checkPair checks the currencies of a rate
+func (s *ExchangeRateService) checkPair(base, quote string) error {
+	if err := checkCurrency(s.validator, base); err != nil {
+		return err
+	}
+	if err := checkCurrency(s.validator, quote); err != nil {
+		return err
+	}
+	if base == quote {
+		return apperrors.BadRequest(i18n.MsgSameCurrency, nil)
+	}
+	return nil
+}
+
+// checkCurrency checks a currency code against ISO 4217
+func checkCurrency(validate *validator.Validate, currency string) error {
+	if err := validate.Var(currency, "required,iso4217"); err != nil {
+		return apperrors.BadRequest(i18n.MsgInvalidCurrency, err)
+	}
+	return nil
+}
+
+// converter converts amounts into one currency with the rates quoted from
+// or into it. A rate quoted from the amount's currency is multiplied by;
+// one quoted the other way is divided by. Either way the result is exact
+// until it is rounded, half away from zero, to the currency's minor unit.
+type converter struct {
+	currency string
+	rates    []models.ExchangeRate
+}
+
+// loadConverter loads the rates for converting into currency. An empty
+// currency returns a nil converter, which leaves amounts as they are.
+func loadConverter(ctx context.Context, rateRepo *repositories.ExchangeRateRepository, validate *validator.Validate, currency string) (*converter, error) {
+	if currency == "" {
+		return nil, nil
+	}
+	if err := checkCurrency(validate, currency); err != nil {
+		return nil, err
+	}
+
+	rates, err := rateRepo.GetByCurrency(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &converter{currency: currency, rates: rates}, nil
+}
+
+// convert converts amount from the from currency, returning the rate it
+// used, or nil when from is already the converter's currency
+func (c *converter) convert(amount money.Amount, from string) (money.Amount, *models.ExchangeRate, error) {
+	if from == c.currency {
+		return amount, nil, nil
+	}
+
+	var converted money.Amount
+	var err error
+	rate := c.rate(from, c.currency)
+	if rate != nil {
+		converted, err = amount.Convert(rate.Rate, c.currency)
+	} else if rate = c.rate(c.currency, from); rate != nil {
+		converted, err = amount.ConvertInverse(rate.Rate, c.currency)
+	} else {
+		return 0, nil, apperrors.BadRequest(i18n.MsgExchangeRateUnavailable, nil)
+	}
+	if errors.Is(err, money.ErrInvalidAmount) {
+		return 0, nil, apperrors.BadRequest(i18n.MsgConversionOutOfRange, err)
+	}
+	return converted, rate, err
+}
+
+// priceRanges converts price bounds in the converter's currency into each
+// currency it has a rate with, so products can be matched on their own
+// price. The bounds are converted with the rate convert uses the other
+// way, rounded to the minor unit of each currency.
+func (c *converter) priceRanges(minPrice, maxPrice *money.Amount) ([]repositories.PriceRange, error) {
+	ranges := []repositories.PriceRange{{Currency: c.currency, Min: minPrice, Max: maxPrice}}
+	seen := map[string]bool{c.currency: true}
+	for _, rate := range c.rates {
+		currency := rate.Base
+		if currency == c.currency {
+			currency = rate.Quote
+		}
+		if seen[currency] {
+			continue
+		}
+		seen[currency] = true
+
+		priceRange := repositories.PriceRange{Currency: currency}
+		var err error
+		if priceRange.Min, err = c.convertInto(minPrice, currency); err != nil {
+			return nil, err
+		}
+		if priceRange.Max, err = c.convertInto(maxPrice, currency); err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, priceRange)
+	}
+	return ranges, nil
+}
+
+// convertInto converts an optional amount from the converter's currency
+// into currency, the inverse of convert
+func (c *converter) convertInto(amount *money.Amount, currency string) (*money.Amount, error) {
+	if amount == nil {
+		return nil, nil
+	}
+
+	var converted money.Amount
+	var err error
+	if rate := c.rate(currency, c.currency); rate != nil {
+		converted, err = amount.ConvertInverse(rate.Rate, currency)
+	} else if rate := c.rate(c.currency, currency); rate != nil {
+		converted, err = amount.Convert(rate.Rate, currency)
+	} else {
+		return nil, apperrors.BadRequest(i18n.MsgExchangeRateUnavailable, nil)
+	}
+	if err != nil {
+		return nil, apperrors.BadRequest(i18n.MsgConversionOutOfRange, err)
+	}
+	return &converted, nil
+}
+
+// convertMoney converts m, leaving it unchanged for a nil converter
+func (c *converter) convertMoney(m money.Money) (money.Money, error) {
+	if c == nil {
+		return m, nil
+	}
+	amount, _, err := c.convert(m.Amount, m.Currency)
+	return money.New(amount, c.currency), err
+}
+
+func (c *converter) rate(base, quote string) *models.ExchangeRate {
+	for i := range c.rates {
+		if c.rates[i].Base == base && c.rates[i].Quote == quote {
+			return &c.rates[i]
+		}
+	}
+	return nil
+}
+
+func exchangeRateResponses(rates []models.ExchangeRate) []models.ExchangeRateResponse {
+	responses := make([]models.ExchangeRateResponse, len(rates))
+	for i, rate := range rates {
+		responses[i] = models.ExchangeRateResponse{
+			Base:      rate.Base,
+			Quote:     rate.Quote,
+			Rate:      rate.Rate,
+			UpdatedAt: rate.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return responses
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/logger"
+	"rest-api/internal/metrics"
+	"rest-api/internal/models"
+	"rest-api/internal/money"
+	"rest-api/internal/repositories"
+	"rest-api/internal/tracing"
+	"rest-api/internal/utils"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderService struct {
+	orderRepo   *repositories.OrderRepository
+	productRepo *repositories.ProductRepository
+	rateRepo    *repositories.ExchangeRateRepository
+	validator   *validator.Validate
+}
+
+func NewOrderService(orderRepo *repositories.OrderRepository, productRepo *repositories.ProductRepository, rateRepo *repositories.ExchangeRateRepository, validator *validator.Validate) *OrderService {
+	return &OrderService{
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		rateRepo:    rateRepo,
+		validator:   validator,
+	}
+}
+
+// PlaceOrder prices the items at the current product and variant prices and
+// takes them from stock, per variant for products with variants. When any
+// item is out of stock, the stock already taken is returned and the order
+// fails with a conflict.
+//
+// A non-empty currency places the order in that currency: item prices are
+// converted at the current exchange rates, each rounded to the currency's
+// minor unit before it is multiplied by the quantity, and the rates are
+// stored with the order so later rate changes do not alter it.
+func (s *OrderService) PlaceOrder(ctx context.Context, userID, currency string, req *models.CreateOrderRequest) (*models.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.PlaceOrder")
+	defer span.End()
+
+	if err := s.validator.Struct(req); err != nil {
+		return nil, apperrors.Validation(err)
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, apperrors.InvalidID(err)
+	}
+
+	conv, err := loadConverter(ctx, s.rateRepo, s.validator, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &models.Order{
+		UserID: userObjID,
+		Status: models.OrderPending,
+	}
+	if err := s.addItems(ctx, order, req.Items, conv); err != nil {
+		return nil, err
+	}
+	items := order.OrderItems
+	for _, item := range items {
+		subtotal, err := item.Price.Mul(item.Quantity)
+		if err == nil {
+			order.TotalAmount, err = order.TotalAmount.Add(subtotal)
+		}
+		if err != nil {
+			return nil, apperrors.BadRequest(i18n.MsgOrderTotalRange, err)
+		}
+	}
+
+	reserved := 0
+	defer func() {
+		if reserved > 0 {
+			s.releaseStock(ctx, items[:reserved])
+		}
+	}()
+	for _, item := range items {
+		if err := s.productRepo.ReserveStock(ctx, item.ProductID, item.VariantID, item.Quantity); err != nil {
+			return nil, err
+		}
+		reserved++
+	}
+
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+	reserved = 0
+
+	logger.FromContext(ctx).Info("order placed", "order_id", order.ID.Hex(), "user_id", userID,
+		"total_amount", order.TotalAmount.String(), "currency", order.Currency)
+	metrics.OrdersPlaced.Inc()
+
+	return convertToOrderResponse(order), nil
+}
+
+// GetOrder returns one of the user's orders; other users' orders are
+// reported as not found
+func (s *OrderService) GetOrder(ctx context.Context, id, userID string) (*models.OrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.GetOrder")
+	defer span.End()
+
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID.Hex() != userID {
+		return nil, apperrors.NotFound(i18n.MsgOrderNotFound)
+	}
+
+	return convertToOrderResponse(order), nil
+}
+
+func (s *OrderService) GetOrdersByUserID(ctx context.Context, userID string, page, limit int) (*models.PaginatedResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.GetOrdersByUserID")
+	defer span.End()
+
+	page, limit = utils.GetPaginationParams(page, limit)
+	offset := utils.CalculateOffset(page, limit)
+
+	orders, total, err := s.orderRepo.GetByUserID(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	orderResponses := make([]models.OrderResponse, len(orders))
+	for i := range orders {
+		orderResponses[i] = *convertToOrderResponse(&orders[i])
+	}
+
+	return &models.PaginatedResponse{
+		Success: true,
+		Message: "Orders retrieved successfully",
+		Data:    orderResponses,
+		Page:    page,
+		Limit:   limit,
+		Total:   total,
+	}, nil
+}
+
+// addItems resolves the requested items against their products, merging
+// repeated lines for the same product and variant, and sets the order's
+// items and currency. Without a converter all the products must share a
+// currency; with one, prices in other currencies are converted and the
+// rates used are added to the order.
+func (s *OrderService) addItems(ctx context.Context, order *models.Order, requests []models.OrderItemRequest, conv *converter) error {
+	type lineKey struct {
+		productID string
+		variantID string
+	}
+	lines := make(map[lineKey]int, len(requests))
+	order.OrderItems = make([]models.OrderItem, 0, len(requests))
+	if conv != nil {
+		order.Currency = conv.currency
+	}
+
+	for _, req := range requests {
+		key := lineKey{req.ProductID, req.VariantID}
+		if i, ok := lines[key]; ok {
+			order.OrderItems[i].Quantity += req.Quantity
+			continue
+		}
+
+		product, err := s.productRepo.GetByID(ctx, req.ProductID)
+		if err != nil {
+			return err
+		}
+		if conv == nil {
+			if order.Currency != "" && product.Currency != order.Currency {
+				return apperrors.BadRequest(i18n.MsgMixedCurrencies, nil)
+			}
+			order.Currency = product.Currency
+		}
+
+		item := models.OrderItem{
+			ProductID: product.ID,
+			Name:      product.Name,
+			Quantity:  req.Quantity,
+			Price:     product.Price,
+		}
+		switch {
+		case len(product.Variants) > 0 && req.VariantID == "":
+			return apperrors.BadRequest(i18n.MsgVariantRequired, nil)
+		case req.VariantID != "":
+			variantID, err := primitive.ObjectIDFromHex(req.VariantID)
+			if err != nil {
+				return apperrors.InvalidID(err)
+			}
+			variant := findVariant(product, variantID)
+			if variant == nil {
+				return apperrors.BadRequest(i18n.MsgUnknownVariant, nil)
+			}
+			item.VariantID = &variant.ID
+			item.SKU = variant.SKU
+			item.Options = variant.Options
+			item.Price = variantPrice(product, variant)
+		}
+
+		if conv != nil && product.Currency != conv.currency {
+			price, rate, err := conv.convert(item.Price, product.Currency)
+			if err != nil {
+				return err
+			}
+			base := money.New(item.Price, product.Currency)
+			item.Price, item.BasePrice = price, &base
+			order.ExchangeRates = appendRate(order.ExchangeRates, *rate)
+		}
+
+		lines[key] = len(order.OrderItems)
+		order.OrderItems = append(order.OrderItems, item)
+	}
+
+	return nil
+}
+
+// appendRate adds rate to rates unless it is listed already
+func appendRate(rates []models.ExchangeRate, rate models.ExchangeRate) []models.ExchangeRate {
+	for _, existing := range rates {
+		if existing.Base == rate.Base && existing.Quote == rate.Quote {
+			return rates
+		}
+	}
+	return append(rates, rate)
+}
+
+// releaseStock returns the stock taken for items. It runs even when the
+// request was cancelled; failures are logged since the order has failed
+// already.
+func (s *OrderService) releaseStock(ctx context.Context, items []models.OrderItem) {
+	ctx = context.WithoutCancel(ctx)
+	for _, item := range items {
+		if err := s.productRepo.ReleaseStock(ctx, item.ProductID, item.VariantID, item.Quantity); err != nil {
+			logger.FromContext(ctx).Error("failed to release stock",
+				"product_id", item.ProductID.Hex(), "quantity", item.Quantity, "error", err)
+		}
+	}
+}
+
+func convertToOrderResponse(order *models.Order) *models.OrderResponse {
+	items := make([]models.OrderItemResponse, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		// PlaceOrder checked that every subtotal fits
+		subtotal, _ := item.Price.Mul(item.Quantity)
+		items[i] = models.OrderItemResponse{
+			ID:        item.ID.Hex(),
+			ProductID: item.ProductID.Hex(),
+			Name:      item.Name,
+			SKU:       item.SKU,
+			Options:   item.Options,
+			Quantity:  item.Quantity,
+			Price:     money.New(item.Price, order.Currency),
+			Subtotal:  money.New(subtotal, order.Currency),
+			BasePrice: item.BasePrice,
+		}
+		if item.VariantID != nil {
+			items[i].VariantID = item.VariantID.Hex()
+		}
+	}
+
+	var rates []models.ExchangeRateResponse
+	if len(order.ExchangeRates) > 0 {
+		rates = exchangeRateResponses(order.ExchangeRates)
+	}
+
+	return &models.OrderResponse{
+		ID:            order.ID.Hex(),
+		UserID:        order.UserID.Hex(),
+		TotalAmount:   money.New(order.TotalAmount, order.Currency),
+		Status:        order.Status,
+		OrderItems:    items,
+		ExchangeRates: rates,
+		CreatedAt:     order.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     order.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/images"
+	"rest-api/internal/logger"
+	"rest-api/internal/models"
+	"rest-api/internal/tracing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UploadProductImage checks the uploaded file, stores it with a thumbnail
+// and appends it to the product's images. The first image of a product
+// becomes its primary image.
+func (s *ProductService) UploadProductImage(ctx context.Context, id, userID string, file io.Reader) (*models.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.UploadProductImage")
+	defer span.End()
+
+	product, err := s.ownProduct(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(product.Images) >= s.images.MaxPerProduct {
+		return nil, apperrors.Conflict(i18n.MsgProductImageLimit)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, s.images.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.images.MaxBytes {
+		return nil, apperrors.TooLarge(i18n.MsgImageTooLarge)
+	}
+
+	processed, err := images.Process(data, s.images)
+	switch {
+	case errors.Is(err, images.ErrUnsupportedType):
+		return nil, apperrors.Unsupported(i18n.MsgImageUnsupportedType, err)
+	case errors.Is(err, images.ErrTooManyPixels):
+		return nil, apperrors.BadRequest(i18n.MsgImageTooManyPixels, err)
+	case errors.Is(err, images.ErrInvalid):
+		return nil, apperrors.BadRequest(i18n.MsgImageInvalid, err)
+	case err != nil:
+		return nil, err
+	}
+
+	image := models.ProductImage{
+		ID:          primitive.NewObjectID(),
+		ContentType: processed.ContentType,
+		Size:        int64(len(data)),
+		Width:       processed.Width,
+		Height:      processed.Height,
+		CreatedAt:   time.Now(),
+	}
+	prefix := fmt.Sprintf("products/%s/%s", product.ID.Hex(), image.ID.Hex())
+	image.Key = prefix + "." + processed.Extension
+	image.ThumbnailKey = prefix + "_thumb." + processed.ThumbnailExtension
+
+	if err := s.storage.Put(ctx, image.Key, bytes.NewReader(data), image.Size, image.ContentType); err != nil {
+		return nil, err
+	}
+	thumbnail := processed.Thumbnail
+	if err := s.storage.Put(ctx, image.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), processed.ThumbnailContentType); err != nil {
+		s.deleteImageFiles(ctx, image)
+		return nil, err
+	}
+
+	if err := s.productRepo.AddImage(ctx, product.ID, image, s.images.MaxPerProduct); err != nil {
+		s.deleteImageFiles(ctx, image)
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("product image uploaded",
+		"product_id", id, "image_id", image.ID.Hex(), "content_type", image.ContentType, "size", image.Size)
+
+	return s.GetProductByID(ctx, id, "")
+}
+
+// ReorderProductImages puts the product's images in the requested order
+func (s *ProductService) ReorderProductImages(ctx context.Context, id, userID string, req *models.ReorderProductImagesRequest) (*models.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.ReorderProductImages")
+	defer span.End()
+
+	if err := s.validator.Struct(req); err != nil {
+		return nil, apperrors.Validation(err)
+	}
+
+	product, err := s.ownProduct(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[primitive.ObjectID]bool, len(product.Images))
+	for _, image := range product.Images {
+		current[image.ID] = true
+	}
+	order := make([]primitive.ObjectID, 0, len(req.ImageIDs))
+	for _, hexID := range req.ImageIDs {
+		imageID, err := primitive.ObjectIDFromHex(hexID)
+		if err != nil {
+			return nil, apperrors.InvalidID(err)
+		}
+		if !current[imageID] {
+			return nil, apperrors.BadRequest(i18n.MsgProductImageOrder, nil)
+		}
+		// Deleting marks the ID as used, so duplicates are rejected
+		delete(current, imageID)
+		order = append(order, imageID)
+	}
+	if len(current) > 0 {
+		return nil, apperrors.BadRequest(i18n.MsgProductImageOrder, nil)
+	}
+
+	if err := s.productRepo.ReorderImages(ctx, product.ID, order); err != nil {
+		return nil, err
+	}
+	return s.GetProductByID(ctx, id, "")
+}
+
+// SetPrimaryProductImage moves an image to the front of the product's
+// images, keeping the order of the others
+func (s *ProductService) SetPrimaryProductImage(ctx context.Context, id, userID, imageID string) (*models.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.SetPrimaryProductImage")
+	defer span.End()
+
+	product, err := s.ownProduct(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	primary, err := findImage(product, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	order := []primitive.ObjectID{primary.ID}
+	for _, image := range product.Images {
+		if image.ID != primary.ID {
+			order = append(order, image.ID)
+		}
+	}
+
+	if err := s.productRepo.ReorderImages(ctx, product.ID, order); err != nil {
+		return nil, err
+	}
+	return s.GetProductByID(ctx, id, "")
+}
+
+// DeleteProductImage removes an image and its stored files. When the
+// primary image is deleted the next one takes its place.
+func (s *ProductService) DeleteProductImage(ctx context.Context, id, userID, imageID string) (*models.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.DeleteProductImage")
+	defer span.End()
+
+	product, err := s.ownProduct(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	image, err := findImage(product, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.RemoveImage(ctx, product.ID, image.ID); err != nil {
+		return nil, err
+	}
+	s.deleteImageFiles(ctx, *image)
+
+	return s.GetProductByID(ctx, id, "")
+}
+
+// ownProduct loads a product the user may change
+func (s *ProductService) ownProduct(ctx context.Context, id, userID string) (*models.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product.UserID.Hex() != userID {
+		return nil, apperrors.Forbidden(i18n.MsgProductUpdateForbidden)
+	}
+	return product, nil
+}
+
+func findImage(product *models.Product, imageID string) (*models.ProductImage, error) {
+	objID, err := primitive.ObjectIDFromHex(imageID)
+	if err != nil {
+		return nil, apperrors.InvalidID(err)
+	}
+	for i := range product.Images {
+		if product.Images[i].ID == objID {
+			return &product.Images[i], nil
+		}
+	}
+	return nil, apperrors.NotFound(i18n.MsgProductImageNotFound)
+}
+
+// deleteImageFiles removes an image and its thumbnail from storage. Failures
+// are logged; the files are unreachable once the image is gone from the
+// product.
+func (s *ProductService) deleteImageFiles(ctx context.Context, image models.ProductImage) {
+	for _, key := range []string{image.Key, image.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.storage.Delete(ctx, key); err != nil {
+			logger.FromContext(ctx).Warn("failed to delete stored image", "key", key, "error", err)
+		}
+	}
+}
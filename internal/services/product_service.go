@@ -1,9 +1,18 @@
 package services
 
 import (
-	"errors"
+	"context"
+	"encoding/json"
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/images"
+	"rest-api/internal/logger"
+	"rest-api/internal/metrics"
 	"rest-api/internal/models"
+	"rest-api/internal/money"
 	"rest-api/internal/repositories"
+	"rest-api/internal/storage"
+	"rest-api/internal/tracing"
 	"rest-api/internal/utils"
 	"time"
 
@@ -12,93 +21,209 @@ import (
 )
 
 type ProductService struct {
-	productRepo *repositories.ProductRepository
-	validator   *validator.Validate
+	productRepo  *repositories.ProductRepository
+	categoryRepo *repositories.CategoryRepository
+	rateRepo     *repositories.ExchangeRateRepository
+	storage      storage.Storage
+	images       images.Config
+	validator    *validator.Validate
 }
 
-func NewProductService(productRepo *repositories.ProductRepository, validator *validator.Validate) *ProductService {
+func NewProductService(productRepo *repositories.ProductRepository, categoryRepo *repositories.CategoryRepository, rateRepo *repositories.ExchangeRateRepository, storage storage.Storage, images images.Config, validator *validator.Validate) *ProductService {
 	return &ProductService{
-		productRepo: productRepo,
-		validator:   validator,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		rateRepo:     rateRepo,
+		storage:      storage,
+		images:       images,
+		validator:    validator,
 	}
 }
 
-func (s *ProductService) CreateProduct(userID string, req *models.CreateProductRequest) (*models.ProductResponse, error) {
+// ProductFilter narrows a product listing
+type ProductFilter struct {
+	// Category is a category slug; empty lists every product
+	Category string
+	// IncludeDescendants also matches products in the category's subcategories
+	IncludeDescendants bool
+	// Tags matches products having all of the tags
+	Tags []string
+	// MinPrice and MaxPrice are in Currency, which they require
+	MinPrice *money.Amount
+	MaxPrice *money.Amount
+	// InStock matches products with stock when true, without when false
+	InStock *bool
+	// Currency converts the listed prices into this currency when set
+	Currency string
+}
+
+func (s *ProductService) CreateProduct(ctx context.Context, userID string, req *models.CreateProductRequest) (*models.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.CreateProduct")
+	defer span.End()
+
 	if err := s.validator.Struct(req); err != nil {
-		return nil, err
+		return nil, apperrors.Validation(err)
 	}
 
 	objID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return nil, errors.New("invalid user ID")
+		return nil, apperrors.InvalidID(err)
+	}
+
+	categoryIDs, err := s.categoryIDs(ctx, req.CategoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	productOptions, variants, err := buildVariants(req.Options, req.Variants, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	product := &models.Product{
 		Name:        req.Name,
 		Description: req.Description,
 		Price:       req.Price,
+		Currency:    req.Currency,
 		Stock:       req.Stock,
 		UserID:      objID,
+		CategoryIDs: categoryIDs,
+		Tags:        utils.NormalizeTags(req.Tags),
+		Options:     productOptions,
+		Variants:    variants,
+	}
+	if product.Currency == "" {
+		product.Currency = money.DefaultCurrency
+	}
+	if len(variants) > 0 {
+		product.Stock = variantStock(variants)
 	}
 
-	if err := s.productRepo.Create(product); err != nil {
+	if err := checkPrices(product); err != nil {
+		return nil, err
+	}
+	if err := s.productRepo.Create(ctx, product); err != nil {
 		return nil, err
 	}
 
+	logger.FromContext(ctx).Info("product created", "product_id", product.ID.Hex(), "user_id", userID)
+	metrics.ProductsCreated.Inc()
+
 	// Load user data
-	product, err = s.productRepo.GetByID(product.ID.Hex())
+	product, err = s.productRepo.GetByID(ctx, product.ID.Hex())
 	if err != nil {
 		return nil, err
 	}
 
-	return s.convertToProductResponse(product), nil
+	return s.productResponse(ctx, product), nil
 }
 
-func (s *ProductService) GetProductByID(id string) (*models.ProductResponse, error) {
-	product, err := s.productRepo.GetByID(id)
+// GetProductByID returns a product, with its prices converted into
+// currency when it is not empty
+func (s *ProductService) GetProductByID(ctx context.Context, id, currency string) (*models.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.GetProductByID")
+	defer span.End()
+
+	conv, err := loadConverter(ctx, s.rateRepo, s.validator, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.convertToProductResponse(product), nil
+	response := s.productResponse(ctx, product)
+	if err := convertPrices(response, conv); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
-func (s *ProductService) GetAllProducts(page, limit int) (*models.PaginatedResponse, error) {
+// GetAllProducts returns a page of the products matching filter, with tag,
+// category, price and availability counts over all of them
+func (s *ProductService) GetAllProducts(ctx context.Context, filter ProductFilter, page, limit int) (*models.ProductListResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.GetAllProducts")
+	defer span.End()
+
 	page, limit = utils.GetPaginationParams(page, limit)
 	offset := utils.CalculateOffset(page, limit)
 
-	products, total, err := s.productRepo.GetAll(offset, limit)
+	conv, err := loadConverter(ctx, s.rateRepo, s.validator, filter.Currency)
 	if err != nil {
 		return nil, err
 	}
 
-	productResponses := make([]models.ProductResponse, len(products))
-	for i, product := range products {
-		productResponses[i] = *s.convertToProductResponse(&product)
+	productFilter := repositories.ProductFilter{
+		Tags:    utils.NormalizeTags(filter.Tags),
+		InStock: filter.InStock,
+	}
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		// Prices are only comparable in one currency
+		if conv == nil {
+			return nil, apperrors.BadRequest(i18n.MsgPriceFilterCurrency, nil)
+		}
+		if productFilter.Prices, err = conv.priceRanges(filter.MinPrice, filter.MaxPrice); err != nil {
+			return nil, err
+		}
+	}
+	if filter.Category != "" {
+		category, err := s.categoryRepo.GetBySlug(ctx, filter.Category)
+		if err != nil {
+			return nil, err
+		}
+		productFilter.CategoryIDs = []primitive.ObjectID{category.ID}
+		if filter.IncludeDescendants {
+			if productFilter.CategoryIDs, err = s.categoryRepo.SubtreeIDs(ctx, category); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	return &models.PaginatedResponse{
-		Success: true,
-		Message: "Products retrieved successfully",
-		Data:    productResponses,
-		Page:    page,
-		Limit:   limit,
-		Total:   total,
+	result, err := s.productRepo.Search(ctx, productFilter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	productResponses, err := s.convertedResponses(ctx, result.Products, conv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProductListResponse{
+		PaginatedResponse: models.PaginatedResponse{
+			Success: true,
+			Message: "Products retrieved successfully",
+			Data:    productResponses,
+			Page:    page,
+			Limit:   limit,
+			Total:   result.Total,
+		},
+		Facets: s.productFacets(ctx, &result.Facets),
 	}, nil
 }
 
-func (s *ProductService) GetProductsByUserID(userID string, page, limit int) (*models.PaginatedResponse, error) {
+func (s *ProductService) GetProductsByUserID(ctx context.Context, userID, currency string, page, limit int) (*models.PaginatedResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.GetProductsByUserID")
+	defer span.End()
+
 	page, limit = utils.GetPaginationParams(page, limit)
 	offset := utils.CalculateOffset(page, limit)
 
-	products, total, err := s.productRepo.GetByUserID(userID, offset, limit)
+	conv, err := loadConverter(ctx, s.rateRepo, s.validator, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	products, total, err := s.productRepo.GetByUserID(ctx, userID, offset, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	productResponses := make([]models.ProductResponse, len(products))
-	for i, product := range products {
-		productResponses[i] = *s.convertToProductResponse(&product)
+	productResponses, err := s.convertedResponses(ctx, products, conv)
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.PaginatedResponse{
@@ -111,18 +236,21 @@ func (s *ProductService) GetProductsByUserID(userID string, page, limit int) (*m
 	}, nil
 }
 
-func (s *ProductService) UpdateProduct(id, userID string, req *models.UpdateProductRequest) (*models.ProductResponse, error) {
+func (s *ProductService) UpdateProduct(ctx context.Context, id, userID string, req *models.UpdateProductRequest) (*models.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.UpdateProduct")
+	defer span.End()
+
 	if err := s.validator.Struct(req); err != nil {
-		return nil, err
+		return nil, apperrors.Validation(err)
 	}
 
-	product, err := s.productRepo.GetByID(id)
+	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if product.UserID.Hex() != userID {
-		return nil, errors.New("you can only update your own products")
+		return nil, apperrors.Forbidden(i18n.MsgProductUpdateForbidden)
 	}
 
 	if req.Name != "" {
@@ -134,36 +262,396 @@ func (s *ProductService) UpdateProduct(id, userID string, req *models.UpdateProd
 	if req.Price > 0 {
 		product.Price = req.Price
 	}
-	if req.Stock >= 0 {
-		product.Stock = req.Stock
+	if req.Currency != "" {
+		product.Currency = req.Currency
+	}
+	if req.Stock != nil {
+		product.Stock = *req.Stock
+	}
+	if req.CategoryIDs != nil {
+		if product.CategoryIDs, err = s.categoryIDs(ctx, req.CategoryIDs); err != nil {
+			return nil, err
+		}
+	}
+	if req.Tags != nil {
+		product.Tags = utils.NormalizeTags(req.Tags)
+	}
+	if req.Options != nil || req.Variants != nil {
+		reqOptions, reqVariants := req.Options, req.Variants
+		// Changing only one of them keeps the other
+		if reqOptions == nil {
+			reqOptions = optionRequests(product.Options)
+		}
+		if reqVariants == nil {
+			reqVariants = variantRequests(product.Variants)
+		}
+		if product.Options, product.Variants, err = buildVariants(reqOptions, reqVariants, product.Variants); err != nil {
+			return nil, err
+		}
+	}
+	if len(product.Variants) > 0 {
+		product.Stock = variantStock(product.Variants)
 	}
 
-	if err := s.productRepo.Update(product); err != nil {
+	if err := checkPrices(product); err != nil {
+		return nil, err
+	}
+	if err := s.productRepo.Update(ctx, product); err != nil {
 		return nil, err
 	}
 
-	return s.convertToProductResponse(product), nil
+	return s.productResponse(ctx, product), nil
 }
 
-func (s *ProductService) DeleteProduct(id, userID string) error {
-	product, err := s.productRepo.GetByID(id)
+func (s *ProductService) PatchProduct(ctx context.Context, id, userID string, patch []byte) (*models.ProductResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.PatchProduct")
+	defer span.End()
+
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.UserID.Hex() != userID {
+		return nil, apperrors.Forbidden(i18n.MsgProductUpdateForbidden)
+	}
+
+	// Apply the merge patch to the current state so absent fields are kept
+	currentCategoryIDs := make([]string, len(product.CategoryIDs))
+	for i, id := range product.CategoryIDs {
+		currentCategoryIDs[i] = id.Hex()
+	}
+	currentOptions := optionRequests(product.Options)
+	currentVariants := variantRequests(product.Variants)
+	current, err := json.Marshal(models.PatchProductRequest{
+		Name:        &product.Name,
+		Description: &product.Description,
+		Price:       &product.Price,
+		Currency:    &product.Currency,
+		Stock:       &product.Stock,
+		CategoryIDs: &currentCategoryIDs,
+		Tags:        &product.Tags,
+		Options:     &currentOptions,
+		Variants:    &currentVariants,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := utils.ApplyMergePatch(current, patch)
+	if err != nil {
+		return nil, apperrors.BadRequest(i18n.MsgInvalidMergePatch, err)
+	}
+
+	var req models.PatchProductRequest
+	if err := json.Unmarshal(merged, &req); err != nil {
+		return nil, apperrors.Validation(err)
+	}
+
+	if err := s.validator.Struct(&req); err != nil {
+		return nil, apperrors.Validation(err)
+	}
+
+	product.Name = *req.Name
+	product.Description = ""
+	if req.Description != nil {
+		product.Description = *req.Description
+	}
+	product.Price = *req.Price
+	product.Currency = *req.Currency
+	product.Stock = *req.Stock
+	product.CategoryIDs = nil
+	if req.CategoryIDs != nil {
+		if product.CategoryIDs, err = s.categoryIDs(ctx, *req.CategoryIDs); err != nil {
+			return nil, err
+		}
+	}
+	product.Tags = nil
+	if req.Tags != nil {
+		product.Tags = utils.NormalizeTags(*req.Tags)
+	}
+	var reqOptions []models.ProductOptionRequest
+	if req.Options != nil {
+		reqOptions = *req.Options
+	}
+	var reqVariants []models.ProductVariantRequest
+	if req.Variants != nil {
+		reqVariants = *req.Variants
+	}
+	if product.Options, product.Variants, err = buildVariants(reqOptions, reqVariants, product.Variants); err != nil {
+		return nil, err
+	}
+	if len(product.Variants) > 0 {
+		product.Stock = variantStock(product.Variants)
+	}
+
+	if err := checkPrices(product); err != nil {
+		return nil, err
+	}
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+
+	return s.productResponse(ctx, product), nil
+}
+
+func (s *ProductService) DeleteProduct(ctx context.Context, id, userID string) error {
+	ctx, span := tracing.Start(ctx, "ProductService.DeleteProduct")
+	defer span.End()
+
+	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if product.UserID.Hex() != userID {
-		return errors.New("you can only delete your own products")
+		return apperrors.Forbidden(i18n.MsgProductDeleteForbidden)
+	}
+
+	if err := s.productRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	for _, image := range product.Images {
+		s.deleteImageFiles(ctx, image)
+	}
+
+	logger.FromContext(ctx).Info("product deleted", "product_id", id, "user_id", userID)
+	return nil
+}
+
+// PurgeOrphanedProducts deletes products owned by users that no longer exist
+func (s *ProductService) PurgeOrphanedProducts(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "ProductService.PurgeOrphanedProducts")
+	defer span.End()
+
+	deleted, err := s.productRepo.DeleteOrphaned(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.FromContext(ctx).Info("orphaned products purged", "count", deleted)
+	return deleted, nil
+}
+
+// categoryIDs parses and de-duplicates the category IDs of a request,
+// failing when any of the categories does not exist
+func (s *ProductService) categoryIDs(ctx context.Context, hexIDs []string) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(hexIDs))
+	seen := make(map[primitive.ObjectID]bool, len(hexIDs))
+	for _, hexID := range hexIDs {
+		id, err := primitive.ObjectIDFromHex(hexID)
+		if err != nil {
+			return nil, apperrors.InvalidID(err)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	categories, err := s.categoryRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) != len(ids) {
+		return nil, apperrors.BadRequest(i18n.MsgUnknownCategory, nil)
+	}
+	return ids, nil
+}
+
+// productFacets converts the repository facet counts, naming the counted
+// categories and listing every price range of each currency counted.
+// Categories that can no longer be loaded are left out.
+func (s *ProductService) productFacets(ctx context.Context, counts *repositories.ProductFacetCounts) models.ProductFacets {
+	facets := models.ProductFacets{
+		Tags:        make([]models.TagFacet, len(counts.Tags)),
+		Categories:  make([]models.CategoryFacet, 0, len(counts.Categories)),
+		PriceRanges: []models.PriceRangeFacet{},
+		Availability: models.AvailabilityFacet{
+			InStock:    counts.InStock,
+			OutOfStock: counts.OutOfStock,
+		},
+	}
+
+	for i, tag := range counts.Tags {
+		facets.Tags[i] = models.TagFacet{Tag: tag.Tag, Count: tag.Count}
+	}
+
+	if len(counts.Categories) > 0 {
+		ids := make([]primitive.ObjectID, len(counts.Categories))
+		for i, category := range counts.Categories {
+			ids[i] = category.CategoryID
+		}
+		categories, err := s.categoryRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			logger.FromContext(ctx).Warn("failed to load facet categories", "error", err)
+		}
+		byID := make(map[primitive.ObjectID]models.Category, len(categories))
+		for _, category := range categories {
+			byID[category.ID] = category
+		}
+		for _, count := range counts.Categories {
+			category, ok := byID[count.CategoryID]
+			if !ok {
+				continue
+			}
+			facets.Categories = append(facets.Categories, models.CategoryFacet{
+				CategorySummary: models.CategorySummary{ID: category.ID.Hex(), Name: category.Name, Slug: category.Slug},
+				Count:           count.Count,
+			})
+		}
+	}
+
+	// The counts are ordered by currency, so each currency's ranges are
+	// added when its first count is reached
+	var currency string
+	var ranges []models.PriceRangeFacet
+	for _, count := range counts.PriceRanges {
+		if count.Currency != currency || ranges == nil {
+			currency = count.Currency
+			start := len(facets.PriceRanges)
+			for i, lower := range repositories.PriceBuckets {
+				priceRange := models.PriceRangeFacet{Currency: currency, Min: lower}
+				if i+1 < len(repositories.PriceBuckets) {
+					upper := repositories.PriceBuckets[i+1]
+					priceRange.Max = &upper
+				}
+				facets.PriceRanges = append(facets.PriceRanges, priceRange)
+			}
+			ranges = facets.PriceRanges[start:]
+		}
+		for i := range ranges {
+			if ranges[i].Min == count.Min {
+				ranges[i].Count = count.Count
+			}
+		}
+	}
+
+	return facets
+}
+
+func (s *ProductService) productResponse(ctx context.Context, product *models.Product) *models.ProductResponse {
+	return &s.productResponses(ctx, []models.Product{*product})[0]
+}
+
+// productResponses converts products, loading the breadcrumbs of all of
+// their categories at once. A failure to load them is logged but does not
+// fail the read.
+func (s *ProductService) productResponses(ctx context.Context, products []models.Product) []models.ProductResponse {
+	var categoryIDs []primitive.ObjectID
+	for _, product := range products {
+		categoryIDs = append(categoryIDs, product.CategoryIDs...)
+	}
+
+	trails, err := loadBreadcrumbs(ctx, s.categoryRepo, categoryIDs)
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to load product categories", "error", err)
 	}
 
-	return s.productRepo.Delete(id)
+	responses := make([]models.ProductResponse, len(products))
+	for i := range products {
+		responses[i] = *s.convertToProductResponse(&products[i], trails)
+	}
+	return responses
 }
 
-func (s *ProductService) convertToProductResponse(product *models.Product) *models.ProductResponse {
+// convertedResponses converts products like productResponses, with their
+// prices converted by conv
+func (s *ProductService) convertedResponses(ctx context.Context, products []models.Product, conv *converter) ([]models.ProductResponse, error) {
+	responses := s.productResponses(ctx, products)
+	for i := range responses {
+		if err := convertPrices(&responses[i], conv); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+// convertPrices converts the product and variant prices of response into
+// the converter's currency, keeping the originals as base prices
+func convertPrices(response *models.ProductResponse, conv *converter) error {
+	if conv == nil || response.Price.Currency == conv.currency {
+		return nil
+	}
+
+	base := response.Price
+	price, err := conv.convertMoney(base)
+	if err != nil {
+		return err
+	}
+	response.Price, response.BasePrice = price, &base
+
+	for i := range response.Variants {
+		variant := &response.Variants[i]
+		base := variant.Price
+		if variant.Price, err = conv.convertMoney(base); err != nil {
+			return err
+		}
+		variant.BasePrice = &base
+	}
+	return nil
+}
+
+func (s *ProductService) convertToProductResponse(product *models.Product, trails map[primitive.ObjectID][]models.CategorySummary) *models.ProductResponse {
+	categories := make([]models.ProductCategory, 0, len(product.CategoryIDs))
+	for _, id := range product.CategoryIDs {
+		trail, ok := trails[id]
+		if !ok || len(trail) == 0 {
+			continue
+		}
+		self := trail[len(trail)-1]
+		categories = append(categories, models.ProductCategory{
+			ID:          self.ID,
+			Name:        self.Name,
+			Slug:        self.Slug,
+			Breadcrumbs: trail,
+		})
+	}
+
+	tags := product.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	productImages := make([]models.ProductImageResponse, len(product.Images))
+	for i, image := range product.Images {
+		productImages[i] = models.ProductImageResponse{
+			ID:           image.ID.Hex(),
+			URL:          s.storage.URL(image.Key),
+			ThumbnailURL: s.storage.URL(image.ThumbnailKey),
+			ContentType:  image.ContentType,
+			Size:         image.Size,
+			Width:        image.Width,
+			Height:       image.Height,
+			Primary:      i == 0,
+			CreatedAt:    image.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	productOptions := product.Options
+	if productOptions == nil {
+		productOptions = []models.ProductOption{}
+	}
+	variants := make([]models.ProductVariantResponse, len(product.Variants))
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		variants[i] = models.ProductVariantResponse{
+			ID:      variant.ID.Hex(),
+			SKU:     variant.SKU,
+			Options: variant.Options,
+			Price:   money.New(variantPrice(product, variant), product.Currency),
+			Stock:   variant.Stock,
+		}
+	}
+
 	return &models.ProductResponse{
 		ID:          product.ID.Hex(),
 		Name:        product.Name,
 		Description: product.Description,
-		Price:       product.Price,
+		Price:       money.New(product.Price, product.Currency),
 		Stock:       product.Stock,
 		User: models.UserResponse{
 			ID:        product.User.ID.Hex(),
@@ -172,7 +660,12 @@ func (s *ProductService) convertToProductResponse(product *models.Product) *mode
 			CreatedAt: product.User.CreatedAt.Format(time.RFC3339),
 			UpdatedAt: product.User.UpdatedAt.Format(time.RFC3339),
 		},
-		CreatedAt: product.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: product.UpdatedAt.Format(time.RFC3339),
+		Categories: categories,
+		Tags:       tags,
+		Images:     productImages,
+		Options:    productOptions,
+		Variants:   variants,
+		CreatedAt:  product.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  product.UpdatedAt.Format(time.RFC3339),
 	}
 }
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"strings"
+
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/models"
+	"rest-api/internal/money"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// buildVariants checks the options and variants of a request against each
+// other and converts them. Every variant must pick one allowed value of each
+// option, no two variants may pick the same values or share a SKU, and a
+// variant keeps the ID of the existing variant it names by ID or by SKU.
+func buildVariants(reqOptions []models.ProductOptionRequest, reqVariants []models.ProductVariantRequest, existing []models.ProductVariant) ([]models.ProductOption, []models.ProductVariant, error) {
+	if len(reqOptions) == 0 && len(reqVariants) == 0 {
+		return nil, nil, nil
+	}
+	if len(reqOptions) == 0 || len(reqVariants) == 0 {
+		return nil, nil, apperrors.BadRequest(i18n.MsgVariantsWithoutOptions, nil)
+	}
+
+	productOptions := make([]models.ProductOption, len(reqOptions))
+	allowed := make(map[string]map[string]bool, len(reqOptions))
+	for i, option := range reqOptions {
+		name := strings.TrimSpace(option.Name)
+		if name == "" || allowed[name] != nil {
+			return nil, nil, apperrors.BadRequest(i18n.MsgDuplicateOption, nil)
+		}
+		values := make(map[string]bool, len(option.Values))
+		productOptions[i] = models.ProductOption{Name: name, Values: make([]string, len(option.Values))}
+		for j, value := range option.Values {
+			value = strings.TrimSpace(value)
+			if value == "" || values[value] {
+				return nil, nil, apperrors.BadRequest(i18n.MsgDuplicateOption, nil)
+			}
+			values[value] = true
+			productOptions[i].Values[j] = value
+		}
+		allowed[name] = values
+	}
+
+	existingByID := make(map[string]primitive.ObjectID, len(existing))
+	existingBySKU := make(map[string]primitive.ObjectID, len(existing))
+	for _, variant := range existing {
+		existingByID[variant.ID.Hex()] = variant.ID
+		existingBySKU[variant.SKU] = variant.ID
+	}
+
+	variants := make([]models.ProductVariant, len(reqVariants))
+	seenIDs := make(map[primitive.ObjectID]bool, len(reqVariants))
+	seenSKUs := make(map[string]bool, len(reqVariants))
+	seenCombinations := make(map[string]bool, len(reqVariants))
+	for i, req := range reqVariants {
+		sku := strings.TrimSpace(req.SKU)
+		if sku == "" || seenSKUs[sku] {
+			return nil, nil, apperrors.BadRequest(i18n.MsgDuplicateSKU, nil)
+		}
+		seenSKUs[sku] = true
+
+		if len(req.Options) != len(productOptions) {
+			return nil, nil, apperrors.BadRequest(i18n.MsgVariantOptions, nil)
+		}
+		variantOptions := make(map[string]string, len(productOptions))
+		var combination strings.Builder
+		for _, option := range productOptions {
+			value := strings.TrimSpace(req.Options[option.Name])
+			if !allowed[option.Name][value] {
+				return nil, nil, apperrors.BadRequest(i18n.MsgVariantOptions, nil)
+			}
+			variantOptions[option.Name] = value
+			combination.WriteString(value)
+			combination.WriteByte(0)
+		}
+		if seenCombinations[combination.String()] {
+			return nil, nil, apperrors.BadRequest(i18n.MsgDuplicateVariant, nil)
+		}
+		seenCombinations[combination.String()] = true
+
+		id, ok := existingBySKU[sku]
+		if req.ID != "" {
+			if id, ok = existingByID[req.ID]; !ok {
+				return nil, nil, apperrors.BadRequest(i18n.MsgUnknownVariant, nil)
+			}
+			if seenIDs[id] {
+				return nil, nil, apperrors.BadRequest(i18n.MsgDuplicateVariant, nil)
+			}
+		}
+		if !ok || seenIDs[id] {
+			id = primitive.NewObjectID()
+		}
+		seenIDs[id] = true
+
+		variants[i] = models.ProductVariant{
+			ID:      id,
+			SKU:     sku,
+			Options: variantOptions,
+			Price:   req.Price,
+			Stock:   req.Stock,
+		}
+	}
+
+	return productOptions, variants, nil
+}
+
+// variantStock is the total stock of the variants
+func variantStock(variants []models.ProductVariant) int {
+	stock := 0
+	for _, variant := range variants {
+		stock += variant.Stock
+	}
+	return stock
+}
+
+// findVariant returns the variant with the given ID, or nil
+func findVariant(product *models.Product, id primitive.ObjectID) *models.ProductVariant {
+	for i := range product.Variants {
+		if product.Variants[i].ID == id {
+			return &product.Variants[i]
+		}
+	}
+	return nil
+}
+
+// variantPrice is the price of the variant, falling back to the product's
+func variantPrice(product *models.Product, variant *models.ProductVariant) money.Amount {
+	if variant != nil && variant.Price != nil {
+		return *variant.Price
+	}
+	return product.Price
+}
+
+// checkPrices rejects product and variant prices with more decimal places
+// than the product's currency has, such as cents in yen
+func checkPrices(product *models.Product) error {
+	decimals := money.Decimals(product.Currency)
+	if product.Price.Round(decimals) != product.Price {
+		return apperrors.BadRequest(i18n.MsgPricePrecision, nil)
+	}
+	for _, variant := range product.Variants {
+		if variant.Price != nil && variant.Price.Round(decimals) != *variant.Price {
+			return apperrors.BadRequest(i18n.MsgPricePrecision, nil)
+		}
+	}
+	return nil
+}
+
+// optionRequests converts stored options back to their request form
+func optionRequests(productOptions []models.ProductOption) []models.ProductOptionRequest {
+	requests := make([]models.ProductOptionRequest, len(productOptions))
+	for i, option := range productOptions {
+		requests[i] = models.ProductOptionRequest{Name: option.Name, Values: option.Values}
+	}
+	return requests
+}
+
+// variantRequests converts stored variants back to their request form
+func variantRequests(variants []models.ProductVariant) []models.ProductVariantRequest {
+	requests := make([]models.ProductVariantRequest, len(variants))
+	for i, variant := range variants {
+		requests[i] = models.ProductVariantRequest{
+			ID:      variant.ID.Hex(),
+			SKU:     variant.SKU,
+			Options: variant.Options,
+			Price:   variant.Price,
+			Stock:   variant.Stock,
+		}
+	}
+	return requests
+}
@@ -1,9 +1,16 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"rest-api/internal/apperrors"
+	"rest-api/internal/i18n"
+	"rest-api/internal/logger"
+	"rest-api/internal/metrics"
 	"rest-api/internal/models"
 	"rest-api/internal/repositories"
+	"rest-api/internal/tracing"
 	"rest-api/internal/utils"
 	"time"
 
@@ -22,15 +29,21 @@ func NewUserService(userRepo *repositories.UserRepository, validator *validator.
 	}
 }
 
-func (s *UserService) CreateUser(req *models.CreateUserRequest) (*models.UserResponse, error) {
+func (s *UserService) CreateUser(ctx context.Context, req *models.CreateUserRequest) (*models.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.CreateUser")
+	defer span.End()
+
 	if err := s.validator.Struct(req); err != nil {
-		return nil, err
+		return nil, apperrors.Validation(err)
 	}
 
 	// Check if user already exists
-	_, err := s.userRepo.GetByEmail(req.Email)
+	_, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err == nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, apperrors.Conflict(i18n.MsgEmailExists)
+	}
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, err
 	}
 
 	// Hash password
@@ -43,73 +56,79 @@ func (s *UserService) CreateUser(req *models.CreateUserRequest) (*models.UserRes
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: hashedPassword,
+		Locale:   req.Locale,
+		Role:     models.RoleUser,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
-	return &models.UserResponse{
-		ID:        user.ID.Hex(),
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	logger.FromContext(ctx).Info("user registered", "user_id", user.ID.Hex())
+	metrics.Registrations.Inc()
+
+	return s.convertToUserResponse(user), nil
 }
 
-func (s *UserService) Login(req *models.LoginRequest, jwtSecret string, jwtExpire int) (*models.LoginResponse, error) {
+func (s *UserService) Login(ctx context.Context, req *models.LoginRequest, jwtSecret string, jwtExpire int) (*models.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Login")
+	defer span.End()
+
 	if err := s.validator.Struct(req); err != nil {
-		return nil, err
+		return nil, apperrors.Validation(err)
 	}
 
-	user, err := s.userRepo.GetByEmail(req.Email)
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, errors.New("invalid email or password")
+		if errors.Is(err, apperrors.ErrNotFound) {
+			logger.FromContext(ctx).Warn("login failed", "reason", "unknown email")
+			metrics.Logins.WithLabelValues("failure").Inc()
+			return nil, apperrors.Unauthorized(i18n.MsgInvalidCredentials)
+		}
+		return nil, err
 	}
 
 	if !utils.CheckPasswordHash(req.Password, user.Password) {
-		return nil, errors.New("invalid email or password")
+		logger.FromContext(ctx).Warn("login failed", "user_id", user.ID.Hex(), "reason", "invalid password")
+		metrics.Logins.WithLabelValues("failure").Inc()
+		return nil, apperrors.Unauthorized(i18n.MsgInvalidCredentials)
 	}
 
-	token, err := utils.GenerateToken(user.ID.Hex(), user.Email, jwtSecret, jwtExpire)
+	token, err := utils.GenerateToken(user.ID.Hex(), user.Email, user.Locale, user.Role, jwtSecret, jwtExpire)
 	if err != nil {
 		return nil, err
 	}
 
+	logger.FromContext(ctx).Info("user logged in", "user_id", user.ID.Hex())
+	metrics.Logins.WithLabelValues("success").Inc()
+
 	return &models.LoginResponse{
 		Token: token,
-		User: models.UserResponse{
-			ID:        user.ID.Hex(),
-			Name:      user.Name,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt.Format(time.RFC3339),
-			UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
-		},
+		User:  *s.convertToUserResponse(user),
 	}, nil
 }
 
-func (s *UserService) GetUserByID(id string) (*models.UserResponse, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *UserService) GetUserByID(ctx context.Context, id string) (*models.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByID")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.UserResponse{
-		ID:        user.ID.Hex(),
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	return s.convertToUserResponse(user), nil
 }
 
-func (s *UserService) UpdateUser(id string, req *models.UpdateUserRequest) (*models.UserResponse, error) {
+func (s *UserService) UpdateUser(ctx context.Context, id string, req *models.UpdateUserRequest) (*models.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.UpdateUser")
+	defer span.End()
+
 	if err := s.validator.Struct(req); err != nil {
-		return nil, err
+		return nil, apperrors.Validation(err)
 	}
 
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -119,48 +138,186 @@ func (s *UserService) UpdateUser(id string, req *models.UpdateUserRequest) (*mod
 	}
 	if req.Email != "" {
 		// Check if email is already taken by another user
-		existingUser, err := s.userRepo.GetByEmail(req.Email)
+		existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 		if err == nil && existingUser.ID.Hex() != id {
-			return nil, errors.New("email is already taken")
+			return nil, apperrors.Conflict(i18n.MsgEmailTaken)
+		}
+		if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return nil, err
 		}
 		user.Email = req.Email
 	}
+	if req.Locale != "" {
+		user.Locale = req.Locale
+	}
 
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
 
-	return &models.UserResponse{
-		ID:        user.ID.Hex(),
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	return s.convertToUserResponse(user), nil
+}
+
+func (s *UserService) PatchUser(ctx context.Context, id string, patch []byte) (*models.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.PatchUser")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the merge patch to the current state so absent fields are kept
+	current := models.PatchUserRequest{
+		Name:  &user.Name,
+		Email: &user.Email,
+	}
+	if user.Locale != "" {
+		current.Locale = &user.Locale
+	}
+
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := utils.ApplyMergePatch(currentJSON, patch)
+	if err != nil {
+		return nil, apperrors.BadRequest(i18n.MsgInvalidMergePatch, err)
+	}
+
+	var req models.PatchUserRequest
+	if err := json.Unmarshal(merged, &req); err != nil {
+		return nil, apperrors.Validation(err)
+	}
+
+	if err := s.validator.Struct(&req); err != nil {
+		return nil, apperrors.Validation(err)
+	}
+
+	if *req.Email != user.Email {
+		// Check if email is already taken by another user
+		existingUser, err := s.userRepo.GetByEmail(ctx, *req.Email)
+		if err == nil && existingUser.ID.Hex() != id {
+			return nil, apperrors.Conflict(i18n.MsgEmailTaken)
+		}
+		if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	user.Name = *req.Name
+	user.Email = *req.Email
+	user.Locale = ""
+	if req.Locale != nil {
+		user.Locale = *req.Locale
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return s.convertToUserResponse(user), nil
 }
 
-func (s *UserService) DeleteUser(id string) error {
-	return s.userRepo.Delete(id)
+func (s *UserService) DeleteUser(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "UserService.DeleteUser")
+	defer span.End()
+
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("user deleted", "user_id", id)
+	return nil
 }
 
-func (s *UserService) GetAllUsers(page, limit int) (*models.PaginatedResponse, error) {
+// CreateAdmin creates an admin account, or promotes the existing user with
+// the same email. The returned flag reports whether a user was created.
+func (s *UserService) CreateAdmin(ctx context.Context, req *models.CreateUserRequest) (*models.UserResponse, bool, error) {
+	ctx, span := tracing.Start(ctx, "UserService.CreateAdmin")
+	defer span.End()
+
+	if err := s.validator.Struct(req); err != nil {
+		return nil, false, apperrors.Validation(err)
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err == nil {
+		if err := s.userRepo.UpdateRole(ctx, user.ID, models.RoleAdmin); err != nil {
+			return nil, false, err
+		}
+		user.Role = models.RoleAdmin
+
+		logger.FromContext(ctx).Info("user promoted to admin", "user_id", user.ID.Hex())
+		return s.convertToUserResponse(user), false, nil
+	}
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, false, err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, false, err
+	}
+
+	user = &models.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: hashedPassword,
+		Locale:   req.Locale,
+		Role:     models.RoleAdmin,
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, false, err
+	}
+
+	logger.FromContext(ctx).Info("admin created", "user_id", user.ID.Hex())
+	return s.convertToUserResponse(user), true, nil
+}
+
+func (s *UserService) ResetPassword(ctx context.Context, email, password string) error {
+	ctx, span := tracing.Start(ctx, "UserService.ResetPassword")
+	defer span.End()
+
+	if err := s.validator.Var(password, "required,min=6"); err != nil {
+		return apperrors.Validation(err)
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("password reset", "user_id", user.ID.Hex())
+	return nil
+}
+
+func (s *UserService) GetAllUsers(ctx context.Context, page, limit int) (*models.PaginatedResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetAllUsers")
+	defer span.End()
+
 	page, limit = utils.GetPaginationParams(page, limit)
 	offset := utils.CalculateOffset(page, limit)
 
-	users, total, err := s.userRepo.GetAll(offset, limit)
+	users, total, err := s.userRepo.GetAll(ctx, offset, limit)
 	if err != nil {
 		return nil, err
 	}
 
 	userResponses := make([]models.UserResponse, len(users))
 	for i, user := range users {
-		userResponses[i] = models.UserResponse{
-			ID:        user.ID.Hex(),
-			Name:      user.Name,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt.Format(time.RFC3339),
-			UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
-		}
+		userResponses[i] = *s.convertToUserResponse(&user)
 	}
 
 	return &models.PaginatedResponse{
@@ -172,3 +329,15 @@ func (s *UserService) GetAllUsers(page, limit int) (*models.PaginatedResponse, e
 		Total:   total,
 	}, nil
 }
+
+func (s *UserService) convertToUserResponse(user *models.User) *models.UserResponse {
+	return &models.UserResponse{
+		ID:        user.ID.Hex(),
+		Name:      user.Name,
+		Email:     user.Email,
+		Locale:    user.Locale,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+	}
+}
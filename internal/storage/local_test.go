@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalPutAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocal(filepath.Join(dir, "uploads"), "/uploads")
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+	ctx := context.Background()
+	key := "products/abc/image.jpg"
+
+	if err := store.Put(ctx, key, strings.NewReader("first"), 5, "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := store.Put(ctx, key, strings.NewReader("second"), 6, "image/jpeg"); err != nil {
+		t.Fatalf("Put over an existing object: %v", err)
+	}
+
+	name := filepath.Join(dir, "uploads", "products", "abc", "image.jpg")
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("read stored file: %v", err)
+	}
+	if string(data) != "second" {
+		t.Errorf("stored %q, want %q", data, "second")
+	}
+	entries, err := os.ReadDir(filepath.Dir(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files, want only the object", len(entries))
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(name); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file still exists after Delete: %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of a missing object: %v", err)
+	}
+}
+
+func TestLocalRejectsInvalidKeys(t *testing.T) {
+	store, err := NewLocal(t.TempDir(), "/uploads")
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "a/../../outside", "a//b", `a\b`} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain"); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q): err = %v, want ErrInvalidKey", key, err)
+		}
+		if err := store.Delete(context.Background(), key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Delete(%q): err = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestLocalURLs(t *testing.T) {
+	tests := []struct {
+		publicURL string
+		url       string
+		servePath string
+	}{
+		{"/uploads", "/uploads/products/a.jpg", "/uploads"},
+		{"/uploads/", "/uploads/products/a.jpg", "/uploads"},
+		{"https://cdn.example.com/files", "https://cdn.example.com/files/products/a.jpg", ""},
+		{"//cdn.example.com", "//cdn.example.com/products/a.jpg", ""},
+	}
+	for _, tt := range tests {
+		store, err := NewLocal(t.TempDir(), tt.publicURL)
+		if err != nil {
+			t.Fatalf("NewLocal: %v", err)
+		}
+		if got := store.URL("products/a.jpg"); got != tt.url {
+			t.Errorf("public URL %q: URL = %q, want %q", tt.publicURL, got, tt.url)
+		}
+		if got := store.ServePath(); got != tt.servePath {
+			t.Errorf("public URL %q: ServePath = %q, want %q", tt.publicURL, got, tt.servePath)
+		}
+	}
+}
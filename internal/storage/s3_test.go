@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// s3Request is a request received by the S3 stand-in
+type s3Request struct {
+	method       string
+	path         string
+	body         string
+	contentType  string
+	cacheControl string
+	signed       bool
+}
+
+// newS3StandIn serves just enough of the S3 API for Put and Delete,
+// recording the requests it receives
+func newS3StandIn(t *testing.T) (*httptest.Server, func() []s3Request) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requests []s3Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, s3Request{
+			method:       r.Method,
+			path:         r.URL.Path,
+			body:         string(body),
+			contentType:  r.Header.Get("Content-Type"),
+			cacheControl: r.Header.Get("Cache-Control"),
+			signed:       strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key/"),
+		})
+		mu.Unlock()
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []s3Request {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]s3Request(nil), requests...)
+	}
+}
+
+// isolateAWS keeps the developer's AWS configuration out of the tests
+func isolateAWS(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	t.Setenv("AWS_CONFIG_FILE", missing)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", missing)
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+}
+
+func testS3Config(endpoint string) S3Config {
+	return S3Config{
+		Bucket:          "product-images",
+		Region:          "us-east-1",
+		Endpoint:        endpoint,
+		PathStyle:       true,
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+	}
+}
+
+func TestS3PutAndDelete(t *testing.T) {
+	isolateAWS(t)
+	server, received := newS3StandIn(t)
+	ctx := context.Background()
+
+	store, err := NewS3(ctx, testS3Config(server.URL), "")
+	if err != nil {
+		t.Fatalf("NewS3: %v", err)
+	}
+
+	if err := store.Put(ctx, "products/abc/image.jpg", strings.NewReader("jpeg bytes"), 10, "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := store.Delete(ctx, "products/abc/image.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Put(ctx, "../outside", strings.NewReader("x"), 1, "text/plain"); err == nil {
+		t.Error("Put with an invalid key succeeded")
+	}
+
+	requests := received()
+	if len(requests) != 2 {
+		t.Fatalf("stand-in received %d requests, want 2", len(requests))
+	}
+	put, del := requests[0], requests[1]
+	if put.method != http.MethodPut || put.path != "/product-images/products/abc/image.jpg" {
+		t.Errorf("Put sent %s %s, want PUT /product-images/products/abc/image.jpg", put.method, put.path)
+	}
+	if put.body != "jpeg bytes" || put.contentType != "image/jpeg" || put.cacheControl != objectCacheControl {
+		t.Errorf("Put sent body %q, Content-Type %q, Cache-Control %q", put.body, put.contentType, put.cacheControl)
+	}
+	if !put.signed {
+		t.Error("Put was not signed with the configured access key")
+	}
+	if del.method != http.MethodDelete || del.path != "/product-images/products/abc/image.jpg" {
+		t.Errorf("Delete sent %s %s, want DELETE /product-images/products/abc/image.jpg", del.method, del.path)
+	}
+}
+
+func TestS3URLs(t *testing.T) {
+	isolateAWS(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		endpoint  string
+		publicURL string
+		want      string
+	}{
+		{"endpoint", "http://minio:9000", "", "http://minio:9000/product-images/products/a.jpg"},
+		{"aws", "", "", "https://product-images.s3.us-east-1.amazonaws.com/products/a.jpg"},
+		{"public URL", "http://minio:9000", "https://cdn.example.com/", "https://cdn.example.com/products/a.jpg"},
+	}
+	for _, tt := range tests {
+		store, err := New(ctx, Config{Backend: "S3", PublicURL: tt.publicURL, S3: testS3Config(tt.endpoint)})
+		if err != nil {
+			t.Fatalf("%s: New: %v", tt.name, err)
+		}
+		if got := store.URL("products/a.jpg"); got != tt.want {
+			t.Errorf("%s: URL = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Package storage keeps uploaded files on the local filesystem or in an
+// S3-compatible bucket
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Backends accepted in STORAGE_BACKEND
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// ErrInvalidKey is returned for keys that are empty, absolute or leave the
+// storage root
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage stores objects under slash-separated keys chosen by the
+// application, e.g. "products/<id>/<image>.jpg"
+type Storage interface {
+	// Put stores an object of size bytes, replacing any existing one. body
+	// should be an io.ReadSeeker so uploads can be signed and retried.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Delete removes an object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients fetch the object from
+	URL(key string) string
+}
+
+// Config selects and configures the storage backend
+type Config struct {
+	Backend string
+	// PublicURL prefixes object keys to form their URLs. For the local
+	// backend a path such as /uploads is served by the API itself.
+	PublicURL string
+	LocalDir  string
+	S3        S3Config
+}
+
+// S3Config configures an S3-compatible bucket. Endpoint and PathStyle
+// point the client at stand-ins such as MinIO; without AccessKeyID the
+// default AWS credential chain is used.
+type S3Config struct {
+	Bucket          string
+	Region          string
+	Endpoint        string
+	PathStyle       bool
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// DefaultPublicURL is the PublicURL of a backend when none is configured:
+// the API serves local files under /uploads, while an empty URL makes S3
+// objects point at the bucket
+func DefaultPublicURL(backend string) string {
+	if strings.EqualFold(backend, BackendLocal) {
+		return "/uploads"
+	}
+	return ""
+}
+
+// New builds the backend selected by cfg
+func New(ctx context.Context, cfg Config) (Storage, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case BackendLocal:
+		return NewLocal(cfg.LocalDir, cfg.PublicURL)
+	case BackendS3:
+		return NewS3(ctx, cfg.S3, cfg.PublicURL)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// cleanKey rejects keys that would escape the storage root
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key || strings.HasPrefix(key, "..") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return key, nil
+}
+
+// joinURL appends the key to a URL prefix
+func joinURL(prefix, key string) string {
+	return strings.TrimSuffix(prefix, "/") + "/" + key
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type commandKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// NewMongoMonitor returns a command monitor that records a client span for
+// every MongoDB command, parented to the span in the operation's context.
+// Command documents are not recorded since they may contain user data.
+func NewMongoMonitor() *event.CommandMonitor {
+	var mu sync.Mutex
+	spans := make(map[commandKey]trace.Span)
+
+	end := func(evt event.CommandFinishedEvent, failure string) {
+		key := commandKey{connectionID: evt.ConnectionID, requestID: evt.RequestID}
+
+		mu.Lock()
+		span, ok := spans[key]
+		delete(spans, key)
+		mu.Unlock()
+
+		if !ok {
+			return
+		}
+		if failure != "" {
+			span.RecordError(errors.New(failure))
+			span.SetStatus(codes.Error, failure)
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			collection, _ := evt.Command.Lookup(evt.CommandName).StringValueOK()
+
+			name := evt.CommandName
+			if collection != "" {
+				name = evt.CommandName + " " + collection
+			}
+
+			_, span := Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemMongoDB,
+					semconv.DBNamespace(evt.DatabaseName),
+					semconv.DBOperationName(evt.CommandName),
+					semconv.DBCollectionName(collection),
+				),
+			)
+
+			mu.Lock()
+			spans[commandKey{connectionID: evt.ConnectionID, requestID: evt.RequestID}] = span
+			mu.Unlock()
+		},
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			end(evt.CommandFinishedEvent, "")
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			end(evt.CommandFinishedEvent, evt.Failure)
+		},
+	}
+}
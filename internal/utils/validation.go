@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"rest-api/internal/i18n"
+	"rest-api/internal/models"
+	"rest-api/internal/openapi"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var slugPattern = regexp.MustCompile(openapi.SlugPattern)
+
+// NewValidator creates a validator that reports fields by their JSON names
+// and has translated messages registered for every supported locale
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	// "slug" accepts lowercase words joined by single hyphens
+	_ = validate.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugPattern.MatchString(fl.Field().String())
+	})
+
+	if err := i18n.RegisterValidationTranslations(validate); err != nil {
+		slog.Warn("failed to register validation translations", "error", err)
+	}
+
+	return validate
+}
+
+// FieldErrors converts validation and JSON type errors into field errors
+// with messages in the given locale. It returns nil when err does not
+// describe invalid fields.
+func FieldErrors(err error, locale string) []models.FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		trans := i18n.Translator(locale)
+		fieldErrors := make([]models.FieldError, len(validationErrs))
+		for i, fe := range validationErrs {
+			fieldErrors[i] = models.FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: fe.Translate(trans),
+			}
+		}
+		return fieldErrors
+	}
+
+	var schemaErr *openapi.ValidationError
+	if errors.As(err, &schemaErr) {
+		fieldErrors := make([]models.FieldError, len(schemaErr.Violations))
+		for i, v := range schemaErr.Violations {
+			fieldErrors[i] = models.FieldError{
+				Field:   v.Field,
+				Rule:    v.Rule,
+				Param:   v.Param,
+				Message: i18n.T(locale, schemaMessages[v.Rule], v.Field, v.Param),
+			}
+		}
+		return fieldErrors
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		typeName := jsonType(typeErr.Type)
+		return []models.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeName,
+			Message: i18n.T(locale, i18n.MsgValidationType, typeErr.Field, typeName),
+		}}
+	}
+
+	return nil
+}
+
+var (
+	jsonUnmarshaler = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// jsonType names the JSON type a Go type is decoded from, so field errors
+// do not expose Go type names. Types that decode themselves, such as
+// amounts and IDs, are written as strings.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if pointer := reflect.PointerTo(t); pointer.Implements(jsonUnmarshaler) || pointer.Implements(textUnmarshaler) {
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// schemaMessages maps OpenAPI schema rules to their message keys
+var schemaMessages = map[string]string{
+	"required":         i18n.MsgSchemaRequired,
+	"type":             i18n.MsgSchemaType,
+	"enum":             i18n.MsgSchemaEnum,
+	"pattern":          i18n.MsgSchemaPattern,
+	"format":           i18n.MsgSchemaFormat,
+	"minimum":          i18n.MsgSchemaMinimum,
+	"maximum":          i18n.MsgSchemaMaximum,
+	"exclusiveMinimum": i18n.MsgSchemaExclusiveMinimum,
+	"exclusiveMaximum": i18n.MsgSchemaExclusiveMaximum,
+	"minLength":        i18n.MsgSchemaMinLength,
+	"maxLength":        i18n.MsgSchemaMaxLength,
+	"minItems":         i18n.MsgSchemaMinItems,
+	"maxItems":         i18n.MsgSchemaMaxItems,
+	"oneOf":            i18n.MsgSchemaOneOf,
+}
+
+// fieldPath strips the top-level struct name from a validator namespace
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
@@ -2,105 +2,80 @@ package main
 
 import (
 	"fmt"
-	"log"
-	"net/http"
+	"log/slog"
+	"os"
 
 	"rest-api/internal/config"
-	"rest-api/internal/handlers"
-	"rest-api/internal/middleware"
-	"rest-api/internal/repositories"
-	"rest-api/internal/services"
-
-	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
+	"rest-api/internal/logger"
 )
 
+const usage = `usage: rest-api [command] [flags]
+
+Commands:
+  serve                          run the HTTP server (default)
+  migrate up|down|status         apply, revert or list database migrations
+  seed --products N              create a demo user with N products
+  create-admin --email EMAIL     create an admin, or promote an existing user
+  reset-password --email EMAIL   set a new password for a user
+  purge-deleted                  remove products left behind by deleted users
+  import-rates --file FILE       set exchange rates from a CSV file of base,quote,rate rows
+  config print                   print the effective configuration
+  openapi                        print the OpenAPI document, failing if routes are undocumented
+`
+
 func main() {
 	// Load configuration
-	cfg := config.LoadConfig()
-
-	// Connect to database
-	db, err := config.ConnectDatabase(cfg)
+	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
 	}
 
-	// Initialize validator
-	validate := validator.New()
-
-	// Initialize repositories
-	userRepo := repositories.NewUserRepository(db)
-	productRepo := repositories.NewProductRepository(db, userRepo)
-
-	// Initialize services
-	userService := services.NewUserService(userRepo, validate)
-	productService := services.NewProductService(productRepo, validate)
-
-	// Initialize handlers
-	userHandler := handlers.NewUserHandler(userService)
-	productHandler := handlers.NewProductHandler(productService)
-
-	// Setup router
-	r := gin.Default()
-
-	// Add middleware
-	r.Use(middleware.CORSMiddleware())
-	r.Use(middleware.ErrorHandler())
-
-	// Add JWT config to context
-	r.Use(func(c *gin.Context) {
-		c.Set("jwt_secret", cfg.JWTSecret)
-		c.Set("jwt_expire", cfg.JWTExpire)
-		c.Next()
-	})
-
-	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":  "ok",
-			"message": "Server is running",
-		})
-	})
-
-	// API routes
-	api := r.Group("/api/v1")
-	{
-		// Auth routes
-		auth := api.Group("/auth")
-		{
-			auth.POST("/register", userHandler.CreateUser)
-			auth.POST("/login", userHandler.Login)
-		}
-
-		// User routes
-		users := api.Group("/users")
-		users.Use(middleware.AuthMiddleware(cfg.JWTSecret))
-		{
-			users.GET("/profile", userHandler.GetProfile)
-			users.PUT("/profile", userHandler.UpdateProfile)
-			users.DELETE("/profile", userHandler.DeleteUser)
-			users.GET("/", userHandler.GetAllUsers)
-		}
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
 
-		// Product routes
-		products := api.Group("/products")
-		{
-			products.GET("/", productHandler.GetAllProducts)
-			products.GET("/:id", productHandler.GetProduct)
+	switch command {
+	case "config":
+		os.Exit(runConfigCommand(cfg, args))
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	}
 
-			// Protected product routes
-			products.Use(middleware.AuthMiddleware(cfg.JWTSecret))
-			products.POST("/", productHandler.CreateProduct)
-			products.GET("/my", productHandler.GetMyProducts)
-			products.PUT("/:id", productHandler.UpdateProduct)
-			products.DELETE("/:id", productHandler.DeleteProduct)
-		}
+	if err := cfg.Validate(); err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
 	}
 
-	// Start server
-	address := fmt.Sprintf(":%s", cfg.ServerPort)
-	log.Printf("Server starting on port %s", cfg.ServerPort)
-	if err := r.Run(address); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	// Initialize logger; admin commands log to stderr to keep stdout for their output
+	logOutput := os.Stderr
+	if command == "serve" {
+		logOutput = os.Stdout
+	}
+	appLogger := logger.New(logOutput, cfg.LogFormat, cfg.LogLevel)
+	slog.SetDefault(appLogger)
+
+	switch command {
+	case "serve":
+		os.Exit(runServe(cfg, appLogger))
+	case "migrate":
+		os.Exit(runMigrate(cfg, args))
+	case "seed":
+		os.Exit(runSeed(cfg, args))
+	case "create-admin":
+		os.Exit(runCreateAdmin(cfg, args))
+	case "reset-password":
+		os.Exit(runResetPassword(cfg, args))
+	case "purge-deleted":
+		os.Exit(runPurgeDeleted(cfg, args))
+	case "import-rates":
+		os.Exit(runImportRates(cfg, args))
+	case "openapi":
+		os.Exit(runOpenAPI(cfg, appLogger, args))
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
 	}
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"rest-api/internal/config"
+	"rest-api/internal/handlers"
+	"rest-api/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// runOpenAPI prints the generated OpenAPI document. Building it fails when
+// a route is missing from the operations table or the other way round.
+func runOpenAPI(cfg *config.Config, appLogger *slog.Logger, args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "usage: rest-api openapi")
+		return 2
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	_, doc, err := newRouter(cfg, appLogger, ratelimit.NewMemoryStore(0), unboundRouteHandlers(cfg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "routes and OpenAPI operations diverge:\n%v\n", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+// unboundRouteHandlers returns handlers without services, for building the
+// router only to inspect its routes; calling them panics
+func unboundRouteHandlers(cfg *config.Config) routeHandlers {
+	return routeHandlers{
+		user:     handlers.NewUserHandler(nil),
+		product:  handlers.NewProductHandler(nil, cfg.Images.MaxBytes),
+		category: handlers.NewCategoryHandler(nil),
+		order:    handlers.NewOrderHandler(nil),
+		rate:     handlers.NewExchangeRateHandler(nil),
+		health:   handlers.NewHealthHandler(nil, nil, cfg.HealthTimeout),
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Register creates a user account. It does not log in.
+func (c *Client) Register(ctx context.Context, req *CreateUserRequest) (*User, error) {
+	var user User
+	if err := c.call(ctx, request{method: http.MethodPost, path: APIPrefix + "/auth/register", body: req}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Login logs in and uses the returned token for later requests. The
+// credentials are kept so the client can log in again when the token
+// expires.
+func (c *Client) Login(ctx context.Context, email, password string) (*LoginResponse, error) {
+	req := &LoginRequest{Email: email, Password: password}
+
+	var resp LoginResponse
+	if err := c.call(ctx, request{method: http.MethodPost, path: APIPrefix + "/auth/login", body: req}, &resp); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.credentials = req
+	c.mu.Unlock()
+	c.setToken(resp.Token)
+	return &resp, nil
+}
+
+// Logout forgets the token and credentials. Tokens are stateless, so the
+// server is not contacted.
+func (c *Client) Logout() {
+	c.mu.Lock()
+	c.token = ""
+	c.tokenExpiry = time.Time{}
+	c.credentials = nil
+	c.mu.Unlock()
+}
+
+// tokenExpiry reads the exp claim of a JWT without verifying it, returning
+// the zero time when the token has none
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
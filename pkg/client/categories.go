@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListCategories returns every category, each parent before its
+// subcategories
+func (c *Client) ListCategories(ctx context.Context) ([]Category, error) {
+	var categories []Category
+	if err := c.call(ctx, request{method: http.MethodGet, path: APIPrefix + "/categories/"}, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// GetCategory returns a category by ID
+func (c *Client) GetCategory(ctx context.Context, id string) (*Category, error) {
+	var category Category
+	if err := c.call(ctx, request{method: http.MethodGet, path: categoryPath(id)}, &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// CreateCategory creates a category; it requires an admin
+func (c *Client) CreateCategory(ctx context.Context, req *CreateCategoryRequest) (*Category, error) {
+	var category Category
+	if err := c.call(ctx, request{method: http.MethodPost, path: APIPrefix + "/categories/", body: req, auth: true}, &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// UpdateCategory updates the non-empty fields of a category, moving it
+// when ParentID is set; it requires an admin
+func (c *Client) UpdateCategory(ctx context.Context, id string, req *UpdateCategoryRequest) (*Category, error) {
+	var category Category
+	if err := c.call(ctx, request{method: http.MethodPut, path: categoryPath(id), body: req, auth: true}, &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// DeleteCategory deletes a category without subcategories; it requires an
+// admin
+func (c *Client) DeleteCategory(ctx context.Context, id string) error {
+	return c.call(ctx, request{method: http.MethodDelete, path: categoryPath(id), auth: true}, nil)
+}
+
+func categoryPath(id string) string {
+	return APIPrefix + "/categories/" + url.PathEscape(id)
+}
@@ -0,0 +1,274 @@
+// Package client is a typed Go client for the REST API. It depends on the
+// standard library only; its request and response types mirror the JSON
+// the server sends and receives.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIPrefix is the path prefix of the versioned API routes
+const APIPrefix = "/api/v1"
+
+// Media types of request and response bodies
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	problemContentType    = "application/problem+json"
+)
+
+// Client calls the API at a base URL. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	userAgent  string
+	locale     string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	credentials *LoginRequest
+	onToken     func(token string)
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithToken sets the bearer token sent with authenticated requests
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+		c.tokenExpiry = tokenExpiry(token)
+	}
+}
+
+// WithCredentials makes the client log in on the first authenticated
+// request, and log in again when the token expires or is rejected
+func WithCredentials(email, password string) Option {
+	return func(c *Client) { c.credentials = &LoginRequest{Email: email, Password: password} }
+}
+
+// WithTokenHook registers a function called with every new token, for
+// callers that persist it between runs
+func WithTokenHook(fn func(token string)) Option {
+	return func(c *Client) { c.onToken = fn }
+}
+
+// WithLocale sets the Accept-Language sent with every request
+func WithLocale(locale string) Option {
+	return func(c *Client) { c.locale = locale }
+}
+
+// WithUserAgent sets the User-Agent sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// New creates a client for the API served at baseURL, e.g.
+// "http://localhost:8080"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme and host are required", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  "rest-api-go-client",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Token returns the current bearer token, or "" when not logged in
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// request describes a single API call
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	// raw is sent as is instead of the JSON encoding of body
+	raw         []byte
+	contentType string
+	auth        bool
+}
+
+// do sends req and decodes a successful response into out, which is the
+// response envelope. Authenticated requests are retried once after logging
+// in again when the server rejects the token.
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	body := req.raw
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("encode request body: %w", err)
+		}
+	}
+
+	token, err := c.authToken(ctx, req.auth)
+	if err != nil {
+		return err
+	}
+
+	err = c.send(ctx, req, body, token, out)
+	var apiErr *Error
+	if req.auth && c.hasCredentials() && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		if token, err = c.login(ctx); err != nil {
+			return err
+		}
+		err = c.send(ctx, req, body, token, out)
+	}
+	return err
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte, token string, out interface{}) error {
+	u := *c.baseURL
+	u.Path += req.path
+	u.RawQuery = req.query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), reader)
+	if err != nil {
+		return err
+	}
+
+	// Failed responses are asked for as RFC 7807 problem documents
+	httpReq.Header.Set("Accept", "application/json, "+problemContentType)
+	if body != nil {
+		contentType := req.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.locale != "" {
+		httpReq.Header.Set("Accept-Language", c.locale)
+	}
+	if c.userAgent != "" {
+		httpReq.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp, data)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// call sends req and decodes the data of the APIResponse envelope into data
+func (c *Client) call(ctx context.Context, req request, data interface{}) error {
+	return c.do(ctx, req, &apiResponse{Data: data})
+}
+
+// authToken returns the token for a request, logging in first when the
+// client has credentials and no valid token
+func (c *Client) authToken(ctx context.Context, auth bool) (string, error) {
+	if !auth {
+		return "", nil
+	}
+
+	c.mu.Lock()
+	token, expiry, credentials := c.token, c.tokenExpiry, c.credentials
+	c.mu.Unlock()
+
+	// Renew a little early so the token does not expire in flight
+	expired := !expiry.IsZero() && time.Until(expiry) < time.Minute
+	if credentials != nil && (token == "" || expired) {
+		return c.login(ctx)
+	}
+	if token == "" {
+		return "", ErrNotLoggedIn
+	}
+	return token, nil
+}
+
+// login logs in with the configured credentials and stores the new token
+func (c *Client) login(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	credentials := c.credentials
+	c.mu.Unlock()
+
+	var resp LoginResponse
+	if err := c.call(ctx, request{method: http.MethodPost, path: APIPrefix + "/auth/login", body: credentials}, &resp); err != nil {
+		return "", err
+	}
+	c.setToken(resp.Token)
+	return resp.Token, nil
+}
+
+func (c *Client) hasCredentials() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.credentials != nil
+}
+
+func (c *Client) setToken(token string) {
+	c.mu.Lock()
+	c.token = token
+	c.tokenExpiry = tokenExpiry(token)
+	onToken := c.onToken
+	c.mu.Unlock()
+
+	if onToken != nil && token != "" {
+		onToken(token)
+	}
+}
+
+// mergePatch describes a PATCH request carrying an RFC 7396 merge patch
+func mergePatch(path string, patch interface{}) request {
+	return request{
+		method:      http.MethodPatch,
+		path:        path,
+		body:        patch,
+		contentType: mergePatchContentType,
+		auth:        true,
+	}
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Errors matched by errors.Is against an *Error, by its status
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrTooLarge     = errors.New("payload too large")
+	ErrUnsupported  = errors.New("unsupported media type")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+)
+
+// ErrNotLoggedIn is returned for authenticated calls on a client without a
+// token or credentials
+var ErrNotLoggedIn = errors.New("not logged in: call Login or configure WithToken or WithCredentials")
+
+// Error is a failed API response
+type Error struct {
+	StatusCode int
+	// Code is the machine-readable code, e.g. "NOT_FOUND"
+	Code    string
+	Message string
+	// Detail is the underlying error reported by the server, if any
+	Detail      string
+	FieldErrors []FieldError
+	RequestID   string
+	// RetryAfter is set for rate limited requests
+	RetryAfter time.Duration
+
+	body []byte
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%d %s", e.StatusCode, e.Message)
+	if e.Code != "" {
+		msg += " (" + e.Code + ")"
+	}
+	if e.Detail != "" && e.Detail != e.Message {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// Is reports whether target is the sentinel error for e's status
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrTooLarge:
+		return e.StatusCode == http.StatusRequestEntityTooLarge
+	case ErrUnsupported:
+		return e.StatusCode == http.StatusUnsupportedMediaType
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// decodeError builds an *Error from a failed response, which is a problem
+// document or an APIResponse envelope, falling back to the status text
+// when the body is neither
+func decodeError(resp *http.Response, body []byte) error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
+		RequestID:  resp.Header.Get("X-Request-ID"),
+		body:       body,
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == problemContentType {
+		var problem problemDetails
+		if json.Unmarshal(body, &problem) == nil {
+			// The detail is the message with the reason, if any, appended
+			if problem.Detail != "" {
+				apiErr.Message = problem.Detail
+			}
+			apiErr.Code = problem.Code
+			apiErr.FieldErrors = problem.Errors
+			if problem.RequestID != "" {
+				apiErr.RequestID = problem.RequestID
+			}
+		}
+		return apiErr
+	}
+
+	var envelope apiResponse
+	if json.Unmarshal(body, &envelope) == nil {
+		if envelope.Message != "" {
+			apiErr.Message = envelope.Message
+		}
+		apiErr.Code = envelope.Code
+		apiErr.Detail = envelope.Error
+		apiErr.FieldErrors = envelope.Errors
+		if envelope.RequestID != "" {
+			apiErr.RequestID = envelope.RequestID
+		}
+	}
+	return apiErr
+}
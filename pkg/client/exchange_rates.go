@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListExchangeRates returns every exchange rate, ordered by currency pair
+func (c *Client) ListExchangeRates(ctx context.Context) ([]ExchangeRate, error) {
+	var rates []ExchangeRate
+	if err := c.call(ctx, request{method: http.MethodGet, path: APIPrefix + "/exchange-rates/"}, &rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// SetExchangeRate sets the price of one unit of base in quote; it requires
+// an admin
+func (c *Client) SetExchangeRate(ctx context.Context, base, quote string, rate Rate) (*ExchangeRate, error) {
+	var exchangeRate ExchangeRate
+	req := request{method: http.MethodPut, path: exchangeRatePath(base, quote), body: &SetExchangeRateRequest{Rate: rate}, auth: true}
+	if err := c.call(ctx, req, &exchangeRate); err != nil {
+		return nil, err
+	}
+	return &exchangeRate, nil
+}
+
+// DeleteExchangeRate deletes the rate of a currency pair; it requires an
+// admin
+func (c *Client) DeleteExchangeRate(ctx context.Context, base, quote string) error {
+	return c.call(ctx, request{method: http.MethodDelete, path: exchangeRatePath(base, quote), auth: true}, nil)
+}
+
+func exchangeRatePath(base, quote string) string {
+	return APIPrefix + "/exchange-rates/" + url.PathEscape(base) + "/" + url.PathEscape(quote)
+}
+
+// currencyQuery is the query converting prices into currency, or nil to
+// keep them in the products' currencies
+func currencyQuery(currency string) url.Values {
+	if currency == "" {
+		return nil
+	}
+	return url.Values{"currency": {currency}}
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Live returns the liveness status and build information
+func (c *Client) Live(ctx context.Context) (*HealthResponse, error) {
+	var health HealthResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/health/live"}, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// Ready returns the readiness status. When a dependency is down the checks
+// are returned along with an *Error for the 503 response.
+func (c *Client) Ready(ctx context.Context) (*HealthResponse, error) {
+	var health HealthResponse
+	err := c.do(ctx, request{method: http.MethodGet, path: "/health/ready"}, &health)
+	if err == nil {
+		return &health, nil
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusServiceUnavailable {
+		if decodeErr := json.Unmarshal(apiErr.body, &health); decodeErr == nil {
+			return &health, err
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AmountScale is the number of decimal places an Amount holds
+const AmountScale = 4
+
+// RateScale is the number of decimal places a Rate holds
+const RateScale = 8
+
+// ErrInvalidAmount is returned for malformed amounts and rates, or ones with
+// more decimal places than they hold
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Amount is an exact decimal with AmountScale decimal places, held as an
+// integer count of 1/10000ths and written in JSON as a string
+type Amount int64
+
+// ParseAmount reads a decimal amount such as "19.99"
+func ParseAmount(s string) (Amount, error) {
+	value, err := parseFixed(s, AmountScale)
+	return Amount(value), err
+}
+
+// Format writes the amount with exactly the given number of decimal places,
+// rounding halves away from zero when it has more
+func (a Amount) Format(decimals int) string {
+	decimals = min(max(decimals, 0), AmountScale)
+	value := int64(a)
+	if step := pow10(AmountScale - decimals); step > 1 {
+		remainder := value % step
+		value -= remainder
+		if 2*remainder >= step {
+			value += step
+		} else if 2*remainder <= -step {
+			value -= step
+		}
+	}
+	return formatFixed(value, AmountScale, decimals)
+}
+
+// String writes the amount without trailing zero decimals
+func (a Amount) String() string {
+	return trimZeros(formatFixed(int64(a), AmountScale, AmountScale))
+}
+
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Amount) UnmarshalText(text []byte) error {
+	amount, err := ParseAmount(string(text))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a plain JSON number; null
+// leaves the amount unchanged
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	text, err := jsonDecimal(data)
+	if err != nil || text == "" {
+		return err
+	}
+	return a.UnmarshalText([]byte(text))
+}
+
+// Rate is an exchange rate, the price of one unit of a currency in another,
+// held as an integer count of 10^-RateScale
+type Rate int64
+
+// ParseRate reads a positive decimal exchange rate such as "0.92"
+func ParseRate(s string) (Rate, error) {
+	value, err := parseFixed(s, RateScale)
+	if err == nil && value <= 0 {
+		err = fmt.Errorf("%w: rate %q is not positive", ErrInvalidAmount, s)
+	}
+	return Rate(value), err
+}
+
+// String writes the rate without trailing zero decimals
+func (r Rate) String() string {
+	return trimZeros(formatFixed(int64(r), RateScale, RateScale))
+}
+
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalText(text []byte) error {
+	rate, err := ParseRate(string(text))
+	if err != nil {
+		return err
+	}
+	*r = rate
+	return nil
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a plain JSON number; null
+// leaves the rate unchanged
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	text, err := jsonDecimal(data)
+	if err != nil || text == "" {
+		return err
+	}
+	return r.UnmarshalText([]byte(text))
+}
+
+// Money is an amount in a currency, identified by its ISO 4217 code
+type Money struct {
+	Amount   Amount `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// String writes the amount with the currency's decimal places followed by
+// the currency, e.g. "19.99 USD"
+func (m Money) String() string {
+	return m.Amount.Format(currencyDecimals(m.Currency)) + " " + m.Currency
+}
+
+// minorDigits lists the currencies whose minor unit is not a hundredth,
+// per ISO 4217
+var minorDigits = map[string]int{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+func currencyDecimals(currency string) int {
+	if digits, ok := minorDigits[strings.ToUpper(currency)]; ok {
+		return digits
+	}
+	return 2
+}
+
+// jsonDecimal returns the text of a JSON string or number, or "" for null
+func jsonDecimal(data []byte) (string, error) {
+	text := string(data)
+	if text == "null" {
+		return "", nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return "", err
+		}
+		if text == "" {
+			return "", fmt.Errorf("%w: empty string", ErrInvalidAmount)
+		}
+	}
+	return text, nil
+}
+
+// parseFixed reads a decimal with at most scale decimal places as an
+// integer count of 10^-scale units
+func parseFixed(s string, scale int) (int64, error) {
+	text := s
+	negative := strings.HasPrefix(text, "-")
+	if negative {
+		text = text[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(text, ".")
+	if whole == "" || (hasFraction && fraction == "") || len(fraction) > scale || !digits(whole) || !digits(fraction) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	fractionUnits, _ := strconv.ParseInt((fraction + strings.Repeat("0", scale))[:scale], 10, 64)
+	unit := pow10(scale)
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (math.MaxInt64-fractionUnits)/unit {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	value := units*unit + fractionUnits
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// formatFixed writes a count of 10^-scale units with the given number of
+// decimal places, which must not exceed scale; extra digits are cut off
+func formatFixed(value int64, scale, decimals int) string {
+	sign := ""
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	unit := pow10(scale)
+	text := sign + strconv.FormatInt(value/unit, 10)
+	if decimals > 0 {
+		text += "." + fmt.Sprintf("%0*d", scale, value%unit)[:decimals]
+	}
+	return text
+}
+
+func trimZeros(text string) string {
+	if !strings.Contains(text, ".") {
+		return text
+	}
+	return strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+}
+
+func pow10(n int) int64 {
+	value := int64(1)
+	for range n {
+		value *= 10
+	}
+	return value
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
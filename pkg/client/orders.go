@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// PlaceOrder orders the items for the current user, taking them from stock.
+// It fails with ErrConflict when any item is out of stock.
+func (c *Client) PlaceOrder(ctx context.Context, req *CreateOrderRequest) (*Order, error) {
+	return c.PlaceOrderIn(ctx, "", req)
+}
+
+// PlaceOrderIn is PlaceOrder in currency, converting the item prices at the
+// current exchange rates; the order keeps the rates it was placed at
+func (c *Client) PlaceOrderIn(ctx context.Context, currency string, req *CreateOrderRequest) (*Order, error) {
+	var order Order
+	httpReq := request{method: http.MethodPost, path: APIPrefix + "/orders/", query: currencyQuery(currency), body: req, auth: true}
+	if err := c.call(ctx, httpReq, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// ListMyOrders returns one page of the current user's orders, newest first
+func (c *Client) ListMyOrders(ctx context.Context, opts *ListOptions) (*Page[Order], error) {
+	return list[Order](ctx, c, request{method: http.MethodGet, path: APIPrefix + "/orders/", auth: true}, opts)
+}
+
+// MyOrders iterates over all of the current user's orders
+func (c *Client) MyOrders(ctx context.Context, limit int) iter.Seq2[Order, error] {
+	return all[Order](ctx, c, request{method: http.MethodGet, path: APIPrefix + "/orders/", auth: true}, limit)
+}
+
+// GetOrder returns one of the current user's orders by ID
+func (c *Client) GetOrder(ctx context.Context, id string) (*Order, error) {
+	var order Order
+	path := APIPrefix + "/orders/" + url.PathEscape(id)
+	if err := c.call(ctx, request{method: http.MethodGet, path: path, auth: true}, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
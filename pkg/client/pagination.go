@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// ListOptions selects a page of a list endpoint. Zero values use the
+// server defaults (page 1, 10 items).
+type ListOptions struct {
+	Page  int
+	Limit int
+}
+
+// query adds the page parameters to base, which may be nil
+func (o *ListOptions) query(base url.Values) url.Values {
+	query := url.Values{}
+	for key, values := range base {
+		query[key] = values
+	}
+	if o == nil {
+		return query
+	}
+	if o.Page > 0 {
+		query.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return query
+}
+
+// Page is one page of a list endpoint
+type Page[T any] struct {
+	Items []T
+	Page  int
+	Limit int
+	Total int64
+}
+
+// HasNext reports whether there are items after this page
+func (p *Page[T]) HasNext() bool {
+	return p.Limit > 0 && len(p.Items) > 0 && int64(p.Page)*int64(p.Limit) < p.Total
+}
+
+// list fetches one page of a paginated endpoint
+func list[T any](ctx context.Context, c *Client, req request, opts *ListOptions) (*Page[T], error) {
+	req.query = opts.query(req.query)
+
+	var items []T
+	resp := paginatedResponse{Data: &items}
+	if err := c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	return &Page[T]{Items: items, Page: resp.Page, Limit: resp.Limit, Total: resp.Total}, nil
+}
+
+// all iterates over every item of a paginated endpoint, fetching pages of
+// limit items as needed. Iteration stops after the first error.
+func all[T any](ctx context.Context, c *Client, req request, limit int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		opts := &ListOptions{Page: 1, Limit: limit}
+		for {
+			page, err := list[T](ctx, c, req, opts)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !page.HasNext() {
+				return
+			}
+			opts.Page = page.Page + 1
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"iter"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListProducts returns one page of products
+func (c *Client) ListProducts(ctx context.Context, opts *ListOptions) (*Page[Product], error) {
+	return list[Product](ctx, c, request{method: http.MethodGet, path: APIPrefix + "/products/"}, opts)
+}
+
+// Products iterates over all products, fetching pages of limit products
+func (c *Client) Products(ctx context.Context, limit int) iter.Seq2[Product, error] {
+	return all[Product](ctx, c, request{method: http.MethodGet, path: APIPrefix + "/products/"}, limit)
+}
+
+// ListProductsInCategory returns one page of the products in the category
+// with the given slug, and in its subcategories with includeDescendants
+func (c *Client) ListProductsInCategory(ctx context.Context, slug string, includeDescendants bool, opts *ListOptions) (*Page[Product], error) {
+	return list[Product](ctx, c, categoryFilter(slug, includeDescendants), opts)
+}
+
+// ProductsInCategory iterates over all products in a category
+func (c *Client) ProductsInCategory(ctx context.Context, slug string, includeDescendants bool, limit int) iter.Seq2[Product, error] {
+	return all[Product](ctx, c, categoryFilter(slug, includeDescendants), limit)
+}
+
+// ProductQuery filters SearchProducts; zero values match every product
+type ProductQuery struct {
+	// Category is a category slug
+	Category           string
+	IncludeDescendants bool
+	// Tags matches products having all of the tags
+	Tags []string
+	// MinPrice and MaxPrice are in Currency, which they require; products
+	// in other currencies are compared at the current exchange rates
+	MinPrice *Amount
+	MaxPrice *Amount
+	InStock  *bool
+	// Currency converts the prices of the results into this currency
+	Currency string
+}
+
+func (q *ProductQuery) values() url.Values {
+	query := url.Values{}
+	if q == nil {
+		return query
+	}
+	if q.Category != "" {
+		query.Set("category", q.Category)
+		if q.IncludeDescendants {
+			query.Set("include_descendants", "true")
+		}
+	}
+	if len(q.Tags) > 0 {
+		query.Set("tags", strings.Join(q.Tags, ","))
+	}
+	if q.MinPrice != nil {
+		query.Set("min_price", q.MinPrice.String())
+	}
+	if q.MaxPrice != nil {
+		query.Set("max_price", q.MaxPrice.String())
+	}
+	if q.InStock != nil {
+		query.Set("in_stock", strconv.FormatBool(*q.InStock))
+	}
+	if q.Currency != "" {
+		query.Set("currency", q.Currency)
+	}
+	return query
+}
+
+// ProductResults is a page of products with the facet counts over every
+// product matching the query
+type ProductResults struct {
+	Page[Product]
+	Facets ProductFacets
+}
+
+// SearchProducts returns one page of the products matching query, which
+// may be nil, along with their facet counts
+func (c *Client) SearchProducts(ctx context.Context, query *ProductQuery, opts *ListOptions) (*ProductResults, error) {
+	req := request{method: http.MethodGet, path: APIPrefix + "/products/"}
+	req.query = opts.query(query.values())
+
+	var items []Product
+	resp := productListResponse{paginatedResponse: paginatedResponse{Data: &items}}
+	if err := c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	return &ProductResults{
+		Page:   Page[Product]{Items: items, Page: resp.Page, Limit: resp.Limit, Total: resp.Total},
+		Facets: resp.Facets,
+	}, nil
+}
+
+// ListMyProducts returns one page of the current user's products
+func (c *Client) ListMyProducts(ctx context.Context, opts *ListOptions) (*Page[Product], error) {
+	return list[Product](ctx, c, request{method: http.MethodGet, path: APIPrefix + "/products/my", auth: true}, opts)
+}
+
+// MyProducts iterates over all of the current user's products
+func (c *Client) MyProducts(ctx context.Context, limit int) iter.Seq2[Product, error] {
+	return all[Product](ctx, c, request{method: http.MethodGet, path: APIPrefix + "/products/my", auth: true}, limit)
+}
+
+// GetProduct returns a product by ID
+func (c *Client) GetProduct(ctx context.Context, id string) (*Product, error) {
+	return c.GetProductIn(ctx, id, "")
+}
+
+// GetProductIn returns a product by ID with its prices converted into
+// currency at the current exchange rates
+func (c *Client) GetProductIn(ctx context.Context, id, currency string) (*Product, error) {
+	var product Product
+	if err := c.call(ctx, request{method: http.MethodGet, path: productPath(id), query: currencyQuery(currency)}, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// CreateProduct creates a product owned by the current user
+func (c *Client) CreateProduct(ctx context.Context, req *CreateProductRequest) (*Product, error) {
+	var product Product
+	if err := c.call(ctx, request{method: http.MethodPost, path: APIPrefix + "/products/", body: req, auth: true}, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// UpdateProduct updates the non-empty fields of a product
+func (c *Client) UpdateProduct(ctx context.Context, id string, req *UpdateProductRequest) (*Product, error) {
+	var product Product
+	if err := c.call(ctx, request{method: http.MethodPut, path: productPath(id), body: req, auth: true}, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// PatchProduct applies a JSON merge patch to a product. patch is encoded
+// as is, so a map can set a field to null.
+func (c *Client) PatchProduct(ctx context.Context, id string, patch interface{}) (*Product, error) {
+	var product Product
+	if err := c.call(ctx, mergePatch(productPath(id), patch), &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// DeleteProduct deletes a product
+func (c *Client) DeleteProduct(ctx context.Context, id string) error {
+	return c.call(ctx, request{method: http.MethodDelete, path: productPath(id), auth: true}, nil)
+}
+
+// UploadProductImage uploads an image read from r, sent with filename as
+// the multipart file name. The server detects the type from the contents.
+func (c *Client) UploadProductImage(ctx context.Context, id, filename string, r io.Reader) (*Product, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("image", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	var product Product
+	req := request{
+		method:      http.MethodPost,
+		path:        productPath(id) + "/images",
+		raw:         body.Bytes(),
+		contentType: form.FormDataContentType(),
+		auth:        true,
+	}
+	if err := c.call(ctx, req, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ReorderProductImages orders a product's images as imageIDs, which must
+// list each of them once; the first becomes the primary image
+func (c *Client) ReorderProductImages(ctx context.Context, id string, imageIDs []string) (*Product, error) {
+	var product Product
+	body := &ReorderProductImagesRequest{ImageIDs: imageIDs}
+	if err := c.call(ctx, request{method: http.MethodPut, path: productPath(id) + "/images", body: body, auth: true}, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// SetPrimaryProductImage makes an image the product's primary image
+func (c *Client) SetPrimaryProductImage(ctx context.Context, id, imageID string) (*Product, error) {
+	var product Product
+	path := productPath(id) + "/images/" + url.PathEscape(imageID) + "/primary"
+	if err := c.call(ctx, request{method: http.MethodPut, path: path, auth: true}, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// DeleteProductImage deletes a product image
+func (c *Client) DeleteProductImage(ctx context.Context, id, imageID string) (*Product, error) {
+	var product Product
+	path := productPath(id) + "/images/" + url.PathEscape(imageID)
+	if err := c.call(ctx, request{method: http.MethodDelete, path: path, auth: true}, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func categoryFilter(slug string, includeDescendants bool) request {
+	query := &ProductQuery{Category: slug, IncludeDescendants: includeDescendants}
+	return request{method: http.MethodGet, path: APIPrefix + "/products/", query: query.values()}
+}
+
+func productPath(id string) string {
+	return APIPrefix + "/products/" + url.PathEscape(id)
+}
@@ -0,0 +1,326 @@
+package client
+
+// Request types
+
+type CreateUserRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	// Locale is "en" or "id"
+	Locale string `json:"locale,omitempty"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// UpdateUserRequest changes the non-empty fields of the current user
+type UpdateUserRequest struct {
+	Name   string `json:"name,omitempty"`
+	Email  string `json:"email,omitempty"`
+	Locale string `json:"locale,omitempty"`
+}
+
+// CreateProductRequest creates a product; with variants, stock is the sum
+// of the variant stocks and may be omitted
+type CreateProductRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       Amount `json:"price"`
+	// Currency is an ISO 4217 code, USD when omitted
+	Currency    string                  `json:"currency,omitempty"`
+	Stock       int                     `json:"stock"`
+	CategoryIDs []string                `json:"category_ids,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Options     []ProductOptionRequest  `json:"options,omitempty"`
+	Variants    []ProductVariantRequest `json:"variants,omitempty"`
+}
+
+// UpdateProductRequest changes the non-empty fields of a product; an empty
+// category_ids, tags, options or variants list removes them all
+type UpdateProductRequest struct {
+	Name        string                  `json:"name,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Price       Amount                  `json:"price,omitempty"`
+	Currency    string                  `json:"currency,omitempty"`
+	Stock       *int                    `json:"stock,omitempty"`
+	CategoryIDs []string                `json:"category_ids,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Options     []ProductOptionRequest  `json:"options,omitempty"`
+	Variants    []ProductVariantRequest `json:"variants,omitempty"`
+}
+
+type ProductOptionRequest struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// ProductVariantRequest describes a variant. On update, a variant keeps its
+// ID when the request gives it, or when its SKU is unchanged.
+type ProductVariantRequest struct {
+	ID      string            `json:"id,omitempty"`
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	Price   *Amount           `json:"price,omitempty"`
+	Stock   int               `json:"stock"`
+}
+
+// ReorderProductImagesRequest lists every image of a product in its new
+// order; the first becomes the primary image
+type ReorderProductImagesRequest struct {
+	ImageIDs []string `json:"image_ids"`
+}
+
+type CreateCategoryRequest struct {
+	Name string `json:"name"`
+	// Slug defaults to one derived from the name
+	Slug        string `json:"slug,omitempty"`
+	Description string `json:"description"`
+	ParentID    string `json:"parent_id,omitempty"`
+}
+
+// UpdateCategoryRequest changes the non-empty fields of a category. A
+// parent_id moves the category with its subtree; an empty one makes it a
+// root category.
+type UpdateCategoryRequest struct {
+	Name        string  `json:"name,omitempty"`
+	Slug        string  `json:"slug,omitempty"`
+	Description string  `json:"description,omitempty"`
+	ParentID    *string `json:"parent_id,omitempty"`
+}
+
+type CreateOrderRequest struct {
+	Items []OrderItemRequest `json:"items"`
+}
+
+// OrderItemRequest orders a quantity of a product, naming the variant when
+// the product has variants
+type OrderItemRequest struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// SetExchangeRateRequest sets the price of one unit of the base currency in
+// the quote currency
+type SetExchangeRateRequest struct {
+	Rate Rate `json:"rate"`
+}
+
+// Response types
+
+type User struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Locale    string `json:"locale,omitempty"`
+	Role      string `json:"role,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type LoginResponse struct {
+	Token string `json:"token"`
+	User  User   `json:"user"`
+}
+
+// Product is a product; BasePrice is set when the prices were converted
+// from its currency into a requested one
+type Product struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Price       Money             `json:"price"`
+	BasePrice   *Money            `json:"base_price,omitempty"`
+	Stock       int               `json:"stock"`
+	User        User              `json:"user"`
+	Categories  []ProductCategory `json:"categories"`
+	Tags        []string          `json:"tags"`
+	Images      []ProductImage    `json:"images"`
+	Options     []ProductOption   `json:"options"`
+	Variants    []ProductVariant  `json:"variants"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
+}
+
+type ProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// ProductVariant is a variant with its effective price. BasePrice is set
+// when the price was converted from the product's currency.
+type ProductVariant struct {
+	ID        string            `json:"id"`
+	SKU       string            `json:"sku"`
+	Options   map[string]string `json:"options"`
+	Price     Money             `json:"price"`
+	BasePrice *Money            `json:"base_price,omitempty"`
+	Stock     int               `json:"stock"`
+}
+
+type ProductImage struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Primary      bool   `json:"primary"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// ProductCategory is a category a product is assigned to, with the trail
+// of categories from the root down to it
+type ProductCategory struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Slug        string            `json:"slug"`
+	Breadcrumbs []CategorySummary `json:"breadcrumbs"`
+}
+
+type CategorySummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type Category struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Slug        string            `json:"slug"`
+	Description string            `json:"description"`
+	ParentID    string            `json:"parent_id,omitempty"`
+	Depth       int               `json:"depth"`
+	Breadcrumbs []CategorySummary `json:"breadcrumbs"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
+}
+
+// ProductFacets counts the products matching a search by tag, category,
+// price range and availability
+type ProductFacets struct {
+	Tags         []TagFacet        `json:"tags"`
+	Categories   []CategoryFacet   `json:"categories"`
+	PriceRanges  []PriceRangeFacet `json:"price_ranges"`
+	Availability AvailabilityFacet `json:"availability"`
+}
+
+type TagFacet struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+type CategoryFacet struct {
+	CategorySummary
+	Count int64 `json:"count"`
+}
+
+// PriceRangeFacet counts products priced in Currency from Min up to but
+// excluding Max; the last range of each currency has no Max
+type PriceRangeFacet struct {
+	Currency string  `json:"currency"`
+	Min      Amount  `json:"min"`
+	Max      *Amount `json:"max"`
+	Count    int64   `json:"count"`
+}
+
+type AvailabilityFacet struct {
+	InStock    int64 `json:"in_stock"`
+	OutOfStock int64 `json:"out_of_stock"`
+}
+
+// Order is an order; ExchangeRates lists the rates its items were
+// converted at when it was placed in another currency than the products
+type Order struct {
+	ID            string         `json:"id"`
+	UserID        string         `json:"user_id"`
+	TotalAmount   Money          `json:"total_amount"`
+	Status        string         `json:"status"`
+	OrderItems    []OrderItem    `json:"order_items"`
+	ExchangeRates []ExchangeRate `json:"exchange_rates,omitempty"`
+	CreatedAt     string         `json:"created_at"`
+	UpdatedAt     string         `json:"updated_at"`
+}
+
+type OrderItem struct {
+	ID        string            `json:"id"`
+	ProductID string            `json:"product_id"`
+	VariantID string            `json:"variant_id,omitempty"`
+	Name      string            `json:"name"`
+	SKU       string            `json:"sku,omitempty"`
+	Options   map[string]string `json:"options,omitempty"`
+	Quantity  int               `json:"quantity"`
+	Price     Money             `json:"price"`
+	Subtotal  Money             `json:"subtotal"`
+	// BasePrice is the product price Price was converted from
+	BasePrice *Money `json:"base_price,omitempty"`
+}
+
+// ExchangeRate is the price of one unit of Base in Quote
+type ExchangeRate struct {
+	Base      string `json:"base"`
+	Quote     string `json:"quote"`
+	Rate      Rate   `json:"rate"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type HealthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+	Build  interface{}            `json:"build"`
+}
+
+type HealthCheck struct {
+	Status    string      `json:"status"`
+	LatencyMS float64     `json:"latency_ms"`
+	Error     string      `json:"error,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// FieldError describes a single invalid field in a request body
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Response envelopes
+
+// apiResponse wraps the data of every response but lists and health checks
+type apiResponse struct {
+	Success   bool         `json:"success"`
+	Message   string       `json:"message"`
+	Data      interface{}  `json:"data,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Code      string       `json:"code,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// problemDetails is the RFC 7807 form of a failed response
+type problemDetails struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+type paginatedResponse struct {
+	Data  interface{} `json:"data"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+	Total int64       `json:"total"`
+}
+
+type productListResponse struct {
+	paginatedResponse
+	Facets ProductFacets `json:"facets"`
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+)
+
+// Profile returns the current user
+func (c *Client) Profile(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.call(ctx, request{method: http.MethodGet, path: APIPrefix + "/users/profile", auth: true}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateProfile updates the non-empty fields of the current user
+func (c *Client) UpdateProfile(ctx context.Context, req *UpdateUserRequest) (*User, error) {
+	var user User
+	if err := c.call(ctx, request{method: http.MethodPut, path: APIPrefix + "/users/profile", body: req, auth: true}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// PatchProfile applies a JSON merge patch to the current user. patch is
+// encoded as is, so a map can set a field to null.
+func (c *Client) PatchProfile(ctx context.Context, patch interface{}) (*User, error) {
+	var user User
+	if err := c.call(ctx, mergePatch(APIPrefix+"/users/profile", patch), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteProfile deletes the current user and logs out
+func (c *Client) DeleteProfile(ctx context.Context) error {
+	if err := c.call(ctx, request{method: http.MethodDelete, path: APIPrefix + "/users/profile", auth: true}, nil); err != nil {
+		return err
+	}
+	c.Logout()
+	return nil
+}
+
+// ListUsers returns one page of users
+func (c *Client) ListUsers(ctx context.Context, opts *ListOptions) (*Page[User], error) {
+	return list[User](ctx, c, request{method: http.MethodGet, path: APIPrefix + "/users/", auth: true}, opts)
+}
+
+// Users iterates over all users, fetching pages of limit users
+func (c *Client) Users(ctx context.Context, limit int) iter.Seq2[User, error] {
+	return all[User](ctx, c, request{method: http.MethodGet, path: APIPrefix + "/users/", auth: true}, limit)
+}
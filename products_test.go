@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rest-api/internal/apperrors"
+	"rest-api/internal/models"
+	"rest-api/internal/money"
+	"rest-api/internal/repositories"
+	"rest-api/pkg/client"
+)
+
+// TestProductUpdateKeepsReservedStock checks that an update based on a read
+// made before an order took stock fails rather than restoring the stock
+func TestProductUpdateKeepsReservedStock(t *testing.T) {
+	a, cfg := newTestApp(t)
+	server := newTestServer(t, a, cfg)
+	ctx := context.Background()
+
+	c := newTestClient(t, server.URL)
+	email := registerUser(t, c, "seller")
+	if _, err := c.Login(ctx, email, testPassword); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	price, _ := client.ParseAmount("9.99")
+	created, err := c.CreateProduct(ctx, &client.CreateProductRequest{Name: "Lamp", Price: price, Stock: 10})
+	if err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+
+	repo := repositories.NewProductRepository(a.db, a.userRepo)
+	stale, err := repo.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if err := repo.ReserveStock(ctx, stale.ID, nil, 3); err != nil {
+		t.Fatalf("ReserveStock: %v", err)
+	}
+
+	stale.Name = "Desk lamp"
+	if err := repo.Update(ctx, stale); !errors.Is(err, apperrors.ErrConflict) {
+		t.Fatalf("Update of a stale product: err = %v, want a conflict", err)
+	}
+
+	// Updating through the API reads the product afresh and succeeds
+	updated, err := c.UpdateProduct(ctx, created.ID, &client.UpdateProductRequest{Name: "Desk lamp"})
+	if err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	if updated.Name != "Desk lamp" || updated.Stock != 7 {
+		t.Errorf("updated product is %q with stock %d, want %q with the reserved stock gone, 7", updated.Name, updated.Stock, "Desk lamp")
+	}
+
+	product, err := c.GetProduct(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetProduct: %v", err)
+	}
+	if product.Stock != 7 {
+		t.Errorf("stock = %d, want 7", product.Stock)
+	}
+}
+
+// TestProductPriceFilterAcrossCurrencies checks that price bounds are read
+// in the requested currency and that price facets are counted per currency
+func TestProductPriceFilterAcrossCurrencies(t *testing.T) {
+	a, cfg := newTestApp(t)
+	server := newTestServer(t, a, cfg)
+	ctx := context.Background()
+
+	c := newTestClient(t, server.URL)
+	email := registerUser(t, c, "trader")
+	if _, err := c.Login(ctx, email, testPassword); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	// One EUR is 1.10 USD; there is no JPY rate
+	rate, _ := money.ParseRate("1.1")
+	if err := repositories.NewExchangeRateRepository(a.db).Set(ctx, &models.ExchangeRate{Base: "EUR", Quote: "USD", Rate: rate}); err != nil {
+		t.Fatalf("set rate: %v", err)
+	}
+
+	ids := make(map[string]string)
+	for _, p := range []struct{ name, price, currency string }{
+		{"cheap", "10", "USD"},
+		{"dear", "100", "USD"},
+		{"euro", "50", "EUR"},
+		{"yen", "1000", "JPY"},
+	} {
+		price, _ := client.ParseAmount(p.price)
+		product, err := c.CreateProduct(ctx, &client.CreateProductRequest{Name: p.name, Price: price, Currency: p.currency, Stock: 1})
+		if err != nil {
+			t.Fatalf("CreateProduct %s: %v", p.name, err)
+		}
+		ids[product.ID] = p.name
+	}
+
+	minPrice, _ := client.ParseAmount("40")
+	maxPrice, _ := client.ParseAmount("60")
+	results, err := c.SearchProducts(ctx, &client.ProductQuery{MinPrice: &minPrice, MaxPrice: &maxPrice, Currency: "USD"}, nil)
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	// 50 EUR is 55 USD; 1000 JPY cannot be compared and is left out
+	if len(results.Items) != 1 || ids[results.Items[0].ID] != "euro" {
+		t.Errorf("40 to 60 USD matched %d products, want only the one priced 50 EUR", len(results.Items))
+	}
+
+	_, err = c.SearchProducts(ctx, &client.ProductQuery{MinPrice: &minPrice}, nil)
+	if !errors.Is(err, client.ErrBadRequest) {
+		t.Errorf("price filter without currency: err = %v, want a bad request", err)
+	}
+
+	results, err = c.SearchProducts(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	counts := make(map[string]int64)
+	currencies := make(map[string]int)
+	for _, priceRange := range results.Facets.PriceRanges {
+		currencies[priceRange.Currency]++
+		if priceRange.Count > 0 {
+			counts[priceRange.Currency+" "+priceRange.Min.String()] = priceRange.Count
+		}
+	}
+	want := map[string]int64{"USD 10": 1, "USD 100": 1, "EUR 50": 1, "JPY 1000": 1}
+	if len(counts) != len(want) {
+		t.Errorf("price ranges with products: %v, want %v", counts, want)
+	}
+	for key, count := range want {
+		if counts[key] != count {
+			t.Errorf("price range %s counts %d, want %d", key, counts[key], count)
+		}
+	}
+	for currency, ranges := range currencies {
+		if ranges != len(repositories.PriceBuckets) {
+			t.Errorf("%s has %d price ranges, want %d", currency, ranges, len(repositories.PriceBuckets))
+		}
+	}
+}
+
+// TestDeleteOrphanedKeepsNewOwners checks that purging orphaned products
+// spares users who registered after the owners were looked up
+func TestDeleteOrphanedKeepsNewOwners(t *testing.T) {
+	a, cfg := newTestApp(t)
+	server := newTestServer(t, a, cfg)
+	ctx := context.Background()
+	price, _ := client.ParseAmount("5")
+
+	createProduct := func(name string) *client.Product {
+		t.Helper()
+		c := newTestClient(t, server.URL)
+		email := registerUser(t, c, name)
+		if _, err := c.Login(ctx, email, testPassword); err != nil {
+			t.Fatalf("Login %s: %v", name, err)
+		}
+		product, err := c.CreateProduct(ctx, &client.CreateProductRequest{Name: name + "'s product", Price: price, Stock: 1})
+		if err != nil {
+			t.Fatalf("CreateProduct %s: %v", name, err)
+		}
+		return product
+	}
+
+	gone := createProduct("gone")
+	kept := createProduct("kept")
+	if err := a.userRepo.Delete(ctx, gone.User.ID); err != nil {
+		t.Fatalf("delete user: %v", err)
+	}
+
+	repo := repositories.NewProductRepository(a.db, a.userRepo)
+	orphaned, err := repo.OrphanedOwners(ctx)
+	if err != nil {
+		t.Fatalf("OrphanedOwners: %v", err)
+	}
+	if len(orphaned) != 1 || orphaned[0].Hex() != gone.User.ID {
+		t.Fatalf("orphaned owners = %v, want only %s", orphaned, gone.User.ID)
+	}
+
+	// A user registering between the lookup and the delete
+	late := createProduct("late")
+
+	deleted, err := repo.DeleteByOwners(ctx, orphaned)
+	if err != nil {
+		t.Fatalf("DeleteByOwners: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted %d products, want 1", deleted)
+	}
+	for _, product := range []*client.Product{kept, late} {
+		if _, err := repo.GetByID(ctx, product.ID); err != nil {
+			t.Errorf("%s: %v, want it kept", product.Name, err)
+		}
+	}
+	if _, err := repo.GetByID(ctx, gone.ID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("orphaned product: err = %v, want not found", err)
+	}
+}
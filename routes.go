@@ -0,0 +1,448 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+
+	"rest-api/internal/buildinfo"
+	"rest-api/internal/config"
+	"rest-api/internal/handlers"
+	"rest-api/internal/metrics"
+	"rest-api/internal/middleware"
+	"rest-api/internal/models"
+	"rest-api/internal/money"
+	"rest-api/internal/openapi"
+	"rest-api/internal/ratelimit"
+	"rest-api/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+type routeHandlers struct {
+	user     *handlers.UserHandler
+	product  *handlers.ProductHandler
+	category *handlers.CategoryHandler
+	order    *handlers.OrderHandler
+	rate     *handlers.ExchangeRateHandler
+	health   *handlers.HealthHandler
+	// uploads is the local storage backend, nil when files are stored elsewhere
+	uploads *storage.Local
+}
+
+// newRouter generates the OpenAPI document from the operations table, then
+// registers the middleware and routes, failing when routes and operations
+// diverge
+func newRouter(cfg *config.Config, appLogger *slog.Logger, rateLimitStore ratelimit.Store, h routeHandlers) (*gin.Engine, *openapi.Document, error) {
+	doc := openapi.Generate(openapi.Info{
+		Title:   "REST API",
+		Version: buildinfo.Version,
+	}, operations)
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, nil, err
+	}
+
+	// Add middleware
+	r.Use(middleware.TracingMiddleware())
+	r.Use(middleware.RequestIDMiddleware(appLogger))
+	r.Use(middleware.AccessLogMiddleware())
+	r.Use(middleware.MetricsMiddleware())
+	r.Use(middleware.RecoveryMiddleware())
+	r.Use(middleware.CORSMiddleware(cfg.CORS))
+	r.Use(middleware.LocaleMiddleware(cfg.DefaultLocale))
+	r.Use(middleware.ErrorHandler())
+
+	// Add JWT config to context
+	r.Use(func(c *gin.Context) {
+		c.Set("jwt_secret", cfg.JWTSecret)
+		c.Set("jwt_expire", cfg.JWTExpire)
+		c.Next()
+	})
+
+	// Health check endpoints
+	r.GET("/health", h.health.Live)
+	r.GET("/health/live", h.health.Live)
+	r.GET("/health/ready", h.health.Ready)
+
+	// Metrics endpoint
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// API routes
+	api := r.Group("/api/v1")
+	api.Use(middleware.RateLimitMiddleware(rateLimitStore, "api", cfg.APIRateLimit, middleware.RateLimitByIP))
+
+	// validate checks requests against the document once the group's other
+	// middleware has run, so unauthenticated requests get 401 rather than
+	// schema errors
+	validate := func(group *gin.RouterGroup) {
+		if cfg.OpenAPI.ValidateRequests {
+			group.Use(middleware.OpenAPIValidationMiddleware(doc, cfg.OpenAPI.ValidateResponses))
+		}
+	}
+	{
+		// Auth routes
+		auth := api.Group("/auth")
+		auth.Use(middleware.RateLimitMiddleware(rateLimitStore, "auth", cfg.AuthRateLimit, middleware.RateLimitByIP))
+		validate(auth)
+		{
+			auth.POST("/register", h.user.CreateUser)
+			auth.POST("/login", h.user.Login)
+		}
+
+		// User routes
+		users := api.Group("/users")
+		users.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+		users.Use(middleware.RateLimitMiddleware(rateLimitStore, "user", cfg.UserRateLimit, middleware.RateLimitByUser))
+		validate(users)
+		{
+			users.GET("/profile", h.user.GetProfile)
+			users.PUT("/profile", h.user.UpdateProfile)
+			users.PATCH("/profile", h.user.PatchProfile)
+			users.DELETE("/profile", h.user.DeleteUser)
+			users.GET("/", h.user.GetAllUsers)
+		}
+
+		// Product routes
+		products := api.Group("/products")
+		{
+			public := products.Group("")
+			validate(public)
+			public.GET("/", h.product.GetAllProducts)
+			public.GET("/:id", h.product.GetProduct)
+
+			// Protected product routes
+			products.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+			products.Use(middleware.RateLimitMiddleware(rateLimitStore, "user", cfg.UserRateLimit, middleware.RateLimitByUser))
+			validate(products)
+			products.POST("/", h.product.CreateProduct)
+			products.GET("/my", h.product.GetMyProducts)
+			products.PUT("/:id", h.product.UpdateProduct)
+			products.PATCH("/:id", h.product.PatchProduct)
+			products.DELETE("/:id", h.product.DeleteProduct)
+			products.POST("/:id/images", h.product.UploadProductImage)
+			products.PUT("/:id/images", h.product.ReorderProductImages)
+			products.PUT("/:id/images/:image_id/primary", h.product.SetPrimaryProductImage)
+			products.DELETE("/:id/images/:image_id", h.product.DeleteProductImage)
+		}
+
+		// Category routes; changing the taxonomy is reserved for admins
+		categories := api.Group("/categories")
+		{
+			public := categories.Group("")
+			validate(public)
+			public.GET("/", h.category.ListCategories)
+			public.GET("/:id", h.category.GetCategory)
+
+			categories.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+			categories.Use(middleware.RequireRole(models.RoleAdmin))
+			categories.Use(middleware.RateLimitMiddleware(rateLimitStore, "user", cfg.UserRateLimit, middleware.RateLimitByUser))
+			validate(categories)
+			categories.POST("/", h.category.CreateCategory)
+			categories.PUT("/:id", h.category.UpdateCategory)
+			categories.DELETE("/:id", h.category.DeleteCategory)
+		}
+
+		// Order routes
+		orders := api.Group("/orders")
+		orders.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+		orders.Use(middleware.RateLimitMiddleware(rateLimitStore, "user", cfg.UserRateLimit, middleware.RateLimitByUser))
+		validate(orders)
+		{
+			orders.POST("/", h.order.PlaceOrder)
+			orders.GET("/", h.order.GetMyOrders)
+			orders.GET("/:id", h.order.GetOrder)
+		}
+
+		// Exchange rate routes; setting rates is reserved for admins
+		rates := api.Group("/exchange-rates")
+		{
+			public := rates.Group("")
+			validate(public)
+			public.GET("/", h.rate.ListExchangeRates)
+
+			rates.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+			rates.Use(middleware.RequireRole(models.RoleAdmin))
+			rates.Use(middleware.RateLimitMiddleware(rateLimitStore, "user", cfg.UserRateLimit, middleware.RateLimitByUser))
+			validate(rates)
+			rates.PUT("/:base/:quote", h.rate.SetExchangeRate)
+			rates.DELETE("/:base/:quote", h.rate.DeleteExchangeRate)
+		}
+	}
+
+	// API documentation and uploaded files, registered last so they are not
+	// part of the check
+	if err := openapi.CheckRoutes(r.Routes(), operations); err != nil {
+		return nil, nil, err
+	}
+
+	docsHandler, err := handlers.NewDocsHandler(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.GET("/openapi.json", docsHandler.Spec)
+	r.GET("/docs/*filepath", docsHandler.UI)
+	if h.uploads != nil && h.uploads.ServePath() != "" {
+		r.StaticFS(h.uploads.ServePath(), gin.Dir(h.uploads.Dir(), false))
+	}
+
+	return r, doc, nil
+}
+
+// apiErrors are the errors every /api/v1 route can return
+func apiErrors(statuses ...int) []int {
+	return append(statuses, http.StatusTooManyRequests, http.StatusInternalServerError)
+}
+
+// operations documents every route registered in newRouter
+var operations = []openapi.Operation{
+	// Health and metrics
+	{
+		Method: "GET", Path: "/health", ID: "health", Tag: "Health",
+		Summary:  "Liveness probe (alias of /health/live)",
+		Response: models.HealthResponse{}, Envelope: openapi.EnvelopeNone,
+	},
+	{
+		Method: "GET", Path: "/health/live", ID: "healthLive", Tag: "Health",
+		Summary:  "Liveness probe",
+		Response: models.HealthResponse{}, Envelope: openapi.EnvelopeNone,
+	},
+	{
+		Method: "GET", Path: "/health/ready", ID: "healthReady", Tag: "Health",
+		Summary:  "Readiness probe",
+		Response: models.HealthResponse{}, Envelope: openapi.EnvelopeNone,
+		Alternate: []int{http.StatusServiceUnavailable},
+	},
+	{
+		Method: "GET", Path: "/metrics", ID: "metrics", Tag: "Health",
+		Summary:  "Prometheus metrics",
+		Response: "", Envelope: openapi.EnvelopeNone, ContentType: "text/plain",
+	},
+
+	// Auth
+	{
+		Method: "POST", Path: "/api/v1/auth/register", ID: "register", Tag: "Auth",
+		Summary: "Register a user",
+		Request: models.CreateUserRequest{},
+		Status:  http.StatusCreated, Response: models.UserResponse{},
+		Errors: apiErrors(http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "POST", Path: "/api/v1/auth/login", ID: "login", Tag: "Auth",
+		Summary:  "Log in and receive a JWT",
+		Request:  models.LoginRequest{},
+		Response: models.LoginResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusUnprocessableEntity),
+	},
+
+	// Users
+	{
+		Method: "GET", Path: "/api/v1/users/profile", ID: "getProfile", Tag: "Users", Auth: true,
+		Summary:  "Get the current user",
+		Response: models.UserResponse{},
+		Errors:   apiErrors(http.StatusUnauthorized, http.StatusNotFound),
+	},
+	{
+		Method: "PUT", Path: "/api/v1/users/profile", ID: "updateProfile", Tag: "Users", Auth: true,
+		Summary:  "Update the current user",
+		Request:  models.UpdateUserRequest{},
+		Response: models.UserResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "PATCH", Path: "/api/v1/users/profile", ID: "patchProfile", Tag: "Users", Auth: true,
+		Summary: "Partially update the current user with a JSON merge patch",
+		Request: models.PatchUserRequest{}, MergePatch: true,
+		Response: models.UserResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "DELETE", Path: "/api/v1/users/profile", ID: "deleteProfile", Tag: "Users", Auth: true,
+		Summary: "Delete the current user",
+		Errors:  apiErrors(http.StatusUnauthorized),
+	},
+	{
+		Method: "GET", Path: "/api/v1/users/", ID: "listUsers", Tag: "Users", Auth: true,
+		Summary:  "List users",
+		Query:    openapi.PageParams,
+		Response: models.UserResponse{}, Envelope: openapi.EnvelopePage,
+		Errors: apiErrors(http.StatusUnauthorized),
+	},
+
+	// Products
+	{
+		Method: "GET", Path: "/api/v1/products/", ID: "listProducts", Tag: "Products",
+		Summary: "List products with facet counts",
+		Description: "Filters combine with AND. The facets count tags, categories, price ranges and availability over every matching product, not just the page. " +
+			"Price bounds are converted into each product's currency at the current exchange rates, leaving out currencies without one; price ranges are counted per currency.",
+		Query:    productListParams,
+		Response: models.ProductResponse{}, Envelope: openapi.EnvelopePage, Page: models.ProductListResponse{},
+		Errors: apiErrors(http.StatusBadRequest, http.StatusNotFound),
+	},
+	{
+		Method: "GET", Path: "/api/v1/products/:id", ID: "getProduct", Tag: "Products",
+		Summary:  "Get a product",
+		Query:    []openapi.Parameter{currencyParam},
+		Response: models.ProductResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusNotFound),
+	},
+	{
+		Method: "POST", Path: "/api/v1/products/", ID: "createProduct", Tag: "Products", Auth: true,
+		Summary:     "Create a product",
+		Description: "A product with variants lists its options and one variant per combination of option values it sells. Each variant has its own SKU, unique across products, and stock; the product stock is their sum.",
+		Request:     models.CreateProductRequest{},
+		Status:      http.StatusCreated, Response: models.ProductResponse{},
+		Errors: apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusConflict, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "GET", Path: "/api/v1/products/my", ID: "listMyProducts", Tag: "Products", Auth: true,
+		Summary:  "List the current user's products",
+		Query:    append(append([]openapi.Parameter{}, openapi.PageParams...), currencyParam),
+		Response: models.ProductResponse{}, Envelope: openapi.EnvelopePage,
+		Errors: apiErrors(http.StatusUnauthorized),
+	},
+	{
+		Method: "PUT", Path: "/api/v1/products/:id", ID: "updateProduct", Tag: "Products", Auth: true,
+		Summary: "Update a product",
+		Description: "Variants listed without an id keep the id of the existing variant with the same SKU. " +
+			"Fails with 409 when the product changed while it was being updated, e.g. by an order taking stock.",
+		Request:  models.UpdateProductRequest{},
+		Response: models.ProductResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "PATCH", Path: "/api/v1/products/:id", ID: "patchProduct", Tag: "Products", Auth: true,
+		Summary:     "Partially update a product with a JSON merge patch",
+		Description: "Fails with 409 when the product changed while it was being updated, e.g. by an order taking stock.",
+		Request:     models.PatchProductRequest{}, MergePatch: true,
+		Response: models.ProductResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "DELETE", Path: "/api/v1/products/:id", ID: "deleteProduct", Tag: "Products", Auth: true,
+		Summary: "Delete a product",
+		Errors:  apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound),
+	},
+	{
+		Method: "POST", Path: "/api/v1/products/:id/images", ID: "uploadProductImage", Tag: "Products", Auth: true,
+		Summary:     "Upload a product image",
+		Description: "Accepts JPEG, PNG, GIF and WebP, detected from the file contents. The first image of a product is its primary image.",
+		Upload:      "image",
+		Status:      http.StatusCreated, Response: models.ProductResponse{},
+		Errors: apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict,
+			http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType),
+	},
+	{
+		Method: "PUT", Path: "/api/v1/products/:id/images", ID: "reorderProductImages", Tag: "Products", Auth: true,
+		Summary:  "Reorder product images; the first becomes the primary image",
+		Request:  models.ReorderProductImagesRequest{},
+		Response: models.ProductResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "PUT", Path: "/api/v1/products/:id/images/:image_id/primary", ID: "setPrimaryProductImage", Tag: "Products", Auth: true,
+		Summary:  "Make an image the primary product image",
+		Response: models.ProductResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict),
+	},
+	{
+		Method: "DELETE", Path: "/api/v1/products/:id/images/:image_id", ID: "deleteProductImage", Tag: "Products", Auth: true,
+		Summary:  "Delete a product image",
+		Response: models.ProductResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound),
+	},
+
+	// Categories
+	{
+		Method: "GET", Path: "/api/v1/categories/", ID: "listCategories", Tag: "Categories",
+		Summary:  "List all categories, each parent before its subcategories",
+		Response: []models.CategoryResponse{},
+		Errors:   apiErrors(),
+	},
+	{
+		Method: "GET", Path: "/api/v1/categories/:id", ID: "getCategory", Tag: "Categories",
+		Summary:  "Get a category",
+		Response: models.CategoryResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusNotFound),
+	},
+	{
+		Method: "POST", Path: "/api/v1/categories/", ID: "createCategory", Tag: "Categories", Auth: true,
+		Summary: "Create a category (admin)",
+		Request: models.CreateCategoryRequest{},
+		Status:  http.StatusCreated, Response: models.CategoryResponse{},
+		Errors: apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "PUT", Path: "/api/v1/categories/:id", ID: "updateCategory", Tag: "Categories", Auth: true,
+		Summary:     "Update or move a category (admin)",
+		Description: "Changes the non-empty fields. parent_id moves the category with its subcategories; an empty parent_id makes it a top-level category.",
+		Request:     models.UpdateCategoryRequest{},
+		Response:    models.CategoryResponse{},
+		Errors:      apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "DELETE", Path: "/api/v1/categories/:id", ID: "deleteCategory", Tag: "Categories", Auth: true,
+		Summary:     "Delete a category without subcategories (admin)",
+		Description: "Products in the category are kept and removed from it.",
+		Errors:      apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict),
+	},
+
+	// Orders
+	{
+		Method: "POST", Path: "/api/v1/orders/", ID: "placeOrder", Tag: "Orders", Auth: true,
+		Summary:     "Place an order",
+		Description: "Items are priced at the current product or variant price. Products with variants are ordered by variant_id, and stock is taken from that variant. Fails with 409 when any item is out of stock, leaving all stock unchanged. Without currency all products must share a currency; with it, unit prices are converted at the current exchange rates, which are stored with the order.",
+		Query:       []openapi.Parameter{currencyParam},
+		Request:     models.CreateOrderRequest{},
+		Status:      http.StatusCreated, Response: models.OrderResponse{},
+		Errors: apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "GET", Path: "/api/v1/orders/", ID: "listMyOrders", Tag: "Orders", Auth: true,
+		Summary:  "List the current user's orders, newest first",
+		Query:    openapi.PageParams,
+		Response: models.OrderResponse{}, Envelope: openapi.EnvelopePage,
+		Errors: apiErrors(http.StatusUnauthorized),
+	},
+	{
+		Method: "GET", Path: "/api/v1/orders/:id", ID: "getOrder", Tag: "Orders", Auth: true,
+		Summary:  "Get one of the current user's orders",
+		Response: models.OrderResponse{},
+		Errors:   apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound),
+	},
+
+	// Exchange rates
+	{
+		Method: "GET", Path: "/api/v1/exchange-rates/", ID: "listExchangeRates", Tag: "Exchange rates",
+		Summary:  "List exchange rates",
+		Response: []models.ExchangeRateResponse{},
+		Errors:   apiErrors(),
+	},
+	{
+		Method: "PUT", Path: "/api/v1/exchange-rates/:base/:quote", ID: "setExchangeRate", Tag: "Exchange rates", Auth: true,
+		Summary:     "Set the price of one unit of base in quote (admin)",
+		Description: "Conversions from quote to base divide by the rate unless that pair has a rate of its own. Orders keep the rates they were placed at.",
+		Request:     models.SetExchangeRateRequest{},
+		Response:    models.ExchangeRateResponse{},
+		Errors:      apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusUnprocessableEntity),
+	},
+	{
+		Method: "DELETE", Path: "/api/v1/exchange-rates/:base/:quote", ID: "deleteExchangeRate", Tag: "Exchange rates", Auth: true,
+		Summary: "Delete an exchange rate (admin)",
+		Errors:  apiErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound),
+	},
+}
+
+// currencyParam converts the prices of a response into another currency
+var currencyParam = openapi.Parameter{Name: "currency", In: "query", Description: "ISO 4217 code to convert prices into at the current exchange rates, rounding half away from zero to its minor unit", Schema: &openapi.Schema{Type: "string", Pattern: openapi.CurrencyPattern}}
+
+// productListParams are the query parameters of GET /products
+var productListParams = append(append([]openapi.Parameter{}, openapi.PageParams...),
+	openapi.Parameter{Name: "category", In: "query", Description: "Only products in the category with this slug", Schema: &openapi.Schema{Type: "string", Pattern: openapi.SlugPattern}},
+	openapi.Parameter{Name: "include_descendants", In: "query", Description: "With category, also products in its subcategories", Schema: &openapi.Schema{Type: "boolean"}},
+	openapi.Parameter{Name: "tags", In: "query", Description: "Comma-separated tags, all of which a product must have", Schema: &openapi.Schema{Type: "string"}},
+	openapi.Parameter{Name: "min_price", In: "query", Description: "Minimum price, inclusive, as a decimal string in currency, which must be given with it", Schema: &openapi.Schema{Type: "string", Pattern: money.Pattern}},
+	openapi.Parameter{Name: "max_price", In: "query", Description: "Maximum price, inclusive, as a decimal string in currency, which must be given with it", Schema: &openapi.Schema{Type: "string", Pattern: money.Pattern}},
+	openapi.Parameter{Name: "in_stock", In: "query", Description: "Only products with (true) or without (false) stock", Schema: &openapi.Schema{Type: "boolean"}},
+	currencyParam,
+)
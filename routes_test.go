@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rest-api/internal/ratelimit"
+)
+
+// TestRoutesMatchOpenAPI fails when a route is registered without an
+// operation in the OpenAPI document, or the document lists an operation
+// that has no route
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	cfg := testConfig(t)
+	r, doc, err := newRouter(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), ratelimit.NewMemoryStore(0), unboundRouteHandlers(cfg))
+	if err != nil {
+		t.Fatalf("newRouter: %v", err)
+	}
+
+	// The document and its UI describe the API rather than being part of it
+	undocumented := map[string]bool{
+		"GET /openapi.json":    true,
+		"GET /docs/{filepath}": true,
+	}
+
+	registered := make(map[string]bool)
+	for _, route := range r.Routes() {
+		path := specPath(route.Path)
+		key := route.Method + " " + path
+		registered[key] = true
+		if undocumented[key] {
+			continue
+		}
+		if doc.Paths[path][strings.ToLower(route.Method)] == nil {
+			t.Errorf("route %s %s has no operation in the OpenAPI document", route.Method, route.Path)
+		}
+	}
+
+	for path, item := range doc.Paths {
+		for method := range item {
+			if key := strings.ToUpper(method) + " " + path; !registered[key] {
+				t.Errorf("operation %s has no route", key)
+			}
+		}
+	}
+}
+
+func TestOpenAPIDocumentServed(t *testing.T) {
+	cfg := testConfig(t)
+	r, doc, err := newRouter(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), ratelimit.NewMemoryStore(0), unboundRouteHandlers(cfg))
+	if err != nil {
+		t.Fatalf("newRouter: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+
+	want, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recorder.Body.Bytes(), want) {
+		t.Error("/openapi.json does not serve the generated document")
+	}
+}
+
+// TestProtectedRoutesAuthenticateBeforeValidating checks that a request
+// without a token gets 401 rather than schema errors that describe the body
+func TestProtectedRoutesAuthenticateBeforeValidating(t *testing.T) {
+	cfg := testConfig(t)
+	r, _, err := newRouter(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), ratelimit.NewMemoryStore(0), unboundRouteHandlers(cfg))
+	if err != nil {
+		t.Fatalf("newRouter: %v", err)
+	}
+
+	for _, body := range []string{`{"name": 42}`, `{not json`} {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/products/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(recorder, req)
+
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("POST %s without a token: status = %d, want %d", body, recorder.Code, http.StatusUnauthorized)
+		}
+	}
+
+	// Public routes are still validated
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/products/not-an-id", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("GET with an invalid ID: status = %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+}
+
+// specPath writes gin path parameters the way OpenAPI paths do, e.g.
+// /products/:id as /products/{id}
+func specPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment != "" && (segment[0] == ':' || segment[0] == '*') {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
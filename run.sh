@@ -17,4 +17,4 @@ createdb rest_api_db 2>/dev/null || echo "Database already exists or couldn't be
 
 # Run the application
 echo "Starting the application..."
-go run main.go
+go run .
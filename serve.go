@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"rest-api/internal/config"
+	"rest-api/internal/handlers"
+	"rest-api/internal/ratelimit"
+	"rest-api/internal/storage"
+	"rest-api/internal/tracing"
+)
+
+// runServe runs the HTTP server until SIGINT or SIGTERM
+func runServe(cfg *config.Config, appLogger *slog.Logger) (exitCode int) {
+	// cleanup runs a shutdown step, failing the exit code when it fails.
+	// Steps are deferred as soon as their resource exists, so a failed
+	// startup releases what it already acquired, and share one deadline
+	// that starts with the first of them.
+	var shutdownCtx context.Context
+	cancelShutdown := func() {}
+	defer func() { cancelShutdown() }()
+	cleanup := func(failure string, step func(ctx context.Context) error) {
+		if shutdownCtx == nil {
+			shutdownCtx, cancelShutdown = context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		}
+		if err := step(shutdownCtx); err != nil {
+			slog.Error(failure, "error", err)
+			exitCode = 1
+		}
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		slog.Error("Failed to initialize tracing", "error", err)
+		return 1
+	}
+	// Flushed last, so spans from closing the database are exported
+	defer cleanup("Failed to flush traces", shutdownTracing)
+
+	// Stop on SIGINT/SIGTERM, including while waiting for the database
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Connect to database and build repositories and services
+	app, err := newApp(ctx, cfg)
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		return 1
+	}
+	defer cleanup("Failed to disconnect from MongoDB", app.close)
+
+	// Apply pending migrations
+	if cfg.MigrateOnStart {
+		applied, err := app.migrator.Up(ctx)
+		if err != nil {
+			slog.Error("Failed to apply migrations", "error", err)
+			return 1
+		}
+		for _, migration := range applied {
+			slog.Info("Migration applied", "version", migration.Version, "description", migration.Description)
+		}
+	}
+
+	// Initialize rate limiter
+	rateLimitStore := ratelimit.NewMemoryStore(time.Minute)
+	defer rateLimitStore.Close()
+
+	// Setup router
+	r, _, err := newRouter(cfg, appLogger, rateLimitStore, app.routeHandlers(cfg))
+	if err != nil {
+		slog.Error("Failed to set up routes", "error", err)
+		return 1
+	}
+
+	// Start server
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.ServerPort),
+		Handler:           r,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server starting", "port", cfg.ServerPort)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		slog.Error("Failed to start server", "error", err)
+		exitCode = 1
+	case <-ctx.Done():
+		slog.Info("Shutdown signal received, draining requests", "timeout", cfg.Server.ShutdownTimeout)
+	}
+
+	// A second signal kills the process immediately
+	stop()
+
+	// Stop accepting connections and wait for in-flight requests; the
+	// deferred steps then stop background workers, close database
+	// connections and flush traces
+	cleanup("Failed to drain HTTP server", func(ctx context.Context) error {
+		if err := server.Shutdown(ctx); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+
+	slog.Info("Server stopped")
+	return exitCode
+}
+
+// routeHandlers builds the HTTP handlers over the app's services
+func (a *app) routeHandlers(cfg *config.Config) routeHandlers {
+	return routeHandlers{
+		user:     handlers.NewUserHandler(a.userService),
+		product:  handlers.NewProductHandler(a.productService, cfg.Images.MaxBytes),
+		category: handlers.NewCategoryHandler(a.categoryService),
+		order:    handlers.NewOrderHandler(a.orderService),
+		rate:     handlers.NewExchangeRateHandler(a.rateService),
+		health:   handlers.NewHealthHandler(a.db.Client(), a.migrator, cfg.HealthTimeout),
+		uploads:  localStorage(a.storage),
+	}
+}
+
+// localStorage returns the local storage backend, whose files the API
+// serves itself, or nil for other backends
+func localStorage(store storage.Storage) *storage.Local {
+	local, _ := store.(*storage.Local)
+	return local
+}
@@ -60,7 +60,7 @@ install_dependencies() {
 build_application() {
     echo "🔨 Building the application..."
     mkdir -p bin
-    go build -o bin/rest-api main.go
+    go build -ldflags "-X rest-api/internal/buildinfo.Commit=$(git rev-parse --short HEAD 2>/dev/null)" -o bin/rest-api .
     echo "✅ Application built successfully"
 }
 
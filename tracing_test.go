@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"rest-api/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingSpanTree(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	exporter := tracing.SetupInMemory("rest-api-test")
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	a, cfg := newTestApp(t)
+	server := newTestServer(t, a, cfg)
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/products/65f000000000000000000000", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	spans := exporter.GetSpans()
+	httpSpan := findSpan(t, spans, "GET /api/v1/products/:id")
+	serviceSpan := findSpan(t, spans, "ProductService.GetProductByID")
+	mongoSpan := findSpan(t, spans, "find products")
+
+	if got := httpSpan.SpanContext.TraceID().String(); got != traceID {
+		t.Errorf("HTTP span trace ID = %s, want the propagated %s", got, traceID)
+	}
+	if httpSpan.SpanKind != trace.SpanKindServer {
+		t.Errorf("HTTP span kind = %s, want server", httpSpan.SpanKind)
+	}
+	if mongoSpan.SpanKind != trace.SpanKindClient {
+		t.Errorf("Mongo span kind = %s, want client", mongoSpan.SpanKind)
+	}
+	assertParent(t, serviceSpan, httpSpan)
+	assertParent(t, mongoSpan, serviceSpan)
+}
+
+// findSpan returns the only recorded span named name
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+
+	var found []tracetest.SpanStub
+	for _, span := range spans {
+		if span.Name == name {
+			found = append(found, span)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("found %d spans named %q, want 1", len(found), name)
+	}
+	return found[0]
+}
+
+func assertParent(t *testing.T, child, parent tracetest.SpanStub) {
+	t.Helper()
+
+	if child.Parent.TraceID() != parent.SpanContext.TraceID() || child.Parent.SpanID() != parent.SpanContext.SpanID() {
+		t.Errorf("span %q is not a child of %q", child.Name, parent.Name)
+	}
+}